@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/IgorBayerl/gdcli/internal/addons"
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(addCmd())
+}
+
+func addCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <source>",
+		Short: "Add an addon to the project",
+		Long: `Add an addon to the dependencies in gdproj.json, install it into
+addons/<name> and enable its editor plugin.
+Examples:
+  gdcli add asset:1709                                # Asset Library ID
+  gdcli add https://github.com/bitwes/Gut.git#v9.3.0  # Git repository and ref
+  gdcli add https://example.com/my_addon.zip          # Zip or tar archive
+  gdcli add ../shared/my_addon                        # Local directory
+  gdcli add https://github.com/user/addons.git --name dialogue
+  gdcli add --global asset:1709                       # Install once for every project`,
+		Args: cobra.ExactArgs(1),
+		Run:  runAdd,
+	}
+	cmd.Flags().String("name", "", "Name of the addon directory, defaults to the name in the source")
+	cmd.Flags().Bool("global", false, "Install the addon into ~/.gdcli/addons and link it into the project")
+	return cmd
+}
+
+func runAdd(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("name")
+	global, _ := cmd.Flags().GetBool("global")
+	src := args[0]
+
+	if global {
+		addGlobal(name, src)
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println("❌ No gdproj.json found")
+		fmt.Println("💡 First create a project with: gdcli init")
+		os.Exit(1)
+	}
+
+	if name != "" {
+		if err := addons.ValidateName(name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("📦 Adding %s...\n", src)
+	name, lock, err := addons.Install(name, src, nil)
+	if err != nil {
+		fmt.Printf("❌ Failed to add addon: %v\n", err)
+		os.Exit(1)
+	}
+
+	if previous, ok := cfg.Dependencies[name]; ok && previous != src {
+		fmt.Printf("Replacing %s (%s)\n", name, previous)
+	}
+	if cfg.Dependencies == nil {
+		cfg.Dependencies = make(map[string]string)
+	}
+	cfg.Dependencies[name] = src
+	if err := config.SaveConfig(cfg); err != nil {
+		fmt.Printf("❌ Error saving gdproj.json: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := pinAddon(name, &lock); err != nil {
+		fmt.Printf("⚠️  Failed to write %s: %v\n", config.LockFile, err)
+	}
+
+	version := ""
+	if lock.Version != "" {
+		version = " " + lock.Version
+	}
+	fmt.Printf("✅ Added %s%s to addons/%s\n", name, version, name)
+}
+
+// addGlobal installs the addon into the global addons directory and, inside a
+// project, opts the project in to it.
+func addGlobal(name, src string) {
+	if name != "" {
+		if err := addons.ValidateName(name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("📦 Adding %s globally...\n", src)
+	name, lock, err := addons.InstallGlobal(name, src)
+	if err != nil {
+		fmt.Printf("❌ Failed to add addon: %v\n", err)
+		os.Exit(1)
+	}
+
+	version := ""
+	if lock.Version != "" {
+		version = " " + lock.Version
+	}
+	fmt.Printf("✅ Installed global addon %s%s\n", name, version)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("💡 Use it in a project by adding \"%s\" to global_addons in its gdproj.json\n", name)
+		fmt.Println("   and running 'gdcli install'")
+		return
+	}
+
+	if _, ok := cfg.Dependencies[name]; ok {
+		fmt.Printf("❌ %s is already a dependency of this project\n", name)
+		fmt.Printf("💡 Remove it first with: gdcli remove %s\n", name)
+		os.Exit(1)
+	}
+
+	enabled := false
+	for _, n := range cfg.GlobalAddons {
+		enabled = enabled || n == name
+	}
+	if !enabled {
+		cfg.GlobalAddons = append(cfg.GlobalAddons, name)
+		if err := config.SaveConfig(cfg); err != nil {
+			fmt.Printf("❌ Error saving gdproj.json: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := linkGlobalAddons(cfg); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// pinAddon records the addon's build in gdproj.lock, or removes it when lock
+// is nil.
+func pinAddon(name string, lock *config.AddonLock) error {
+	projectLock, err := config.LoadLock()
+	if err != nil {
+		projectLock = &config.GodotLock{}
+	}
+
+	if lock != nil {
+		if projectLock.Addons == nil {
+			projectLock.Addons = make(map[string]config.AddonLock)
+		}
+		projectLock.Addons[name] = *lock
+	} else {
+		delete(projectLock.Addons, name)
+	}
+
+	return config.SaveLock(projectLock)
+}
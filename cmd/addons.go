@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/IgorBayerl/gdcli/internal/addons"
+	"github.com/IgorBayerl/gdcli/internal/config"
+)
+
+// installAddons installs the addons listed in gdproj.json. Addons pinned in
+// gdproj.lock are installed at the pinned build, others are resolved and
+// pinned. With frozen set, the lock must pin exactly the addons of the config
+// and is never written.
+func installAddons(cfg *config.GodotConfig, frozen bool) error {
+	lock, err := config.LoadLock()
+	if err != nil {
+		lock = &config.GodotLock{}
+	}
+	if lock.Addons == nil {
+		lock.Addons = make(map[string]config.AddonLock)
+	}
+
+	var stale []string
+	for name := range lock.Addons {
+		if _, ok := cfg.Dependencies[name]; !ok {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	if len(stale) > 0 && frozen {
+		return fmt.Errorf("--frozen-lockfile: addon %s is pinned in %s but not in gdproj.json", stale[0], config.LockFile)
+	}
+
+	changed := len(stale) > 0
+	for _, name := range stale {
+		delete(lock.Addons, name)
+	}
+
+	var names []string
+	for name := range cfg.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		src := cfg.Dependencies[name]
+
+		locked, ok := lock.Addons[name]
+		ok = ok && locked.Source == src
+		if !ok && frozen {
+			return fmt.Errorf("--frozen-lockfile: addon %s (%s) is not pinned in %s", name, src, config.LockFile)
+		}
+
+		if ok && addons.IsInstalled(name, locked) {
+			fmt.Printf("Addon %s is up to date\n", name)
+			continue
+		}
+
+		var pinned *config.AddonLock
+		if ok {
+			pinned = &locked
+		}
+
+		fmt.Printf("📦 Installing addon %s...\n", name)
+		if _, resolved, err := addons.Install(name, src, pinned); err != nil {
+			return fmt.Errorf("failed to install addon %s: %v", name, err)
+		} else if !ok {
+			lock.Addons[name] = resolved
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := config.SaveLock(lock); err != nil {
+			return fmt.Errorf("failed to write %s: %v", config.LockFile, err)
+		}
+	}
+
+	return linkGlobalAddons(cfg)
+}
+
+// linkGlobalAddons links the global addons the project opted in to into
+// addons/ and keeps them out of git.
+func linkGlobalAddons(cfg *config.GodotConfig) error {
+	for _, name := range cfg.GlobalAddons {
+		if !addons.IsGlobalInstalled(name) {
+			return fmt.Errorf("global addon %s is not installed\n💡 Install it with: gdcli add --global <source> --name %s", name, name)
+		}
+		if err := addons.LinkGlobal(name); err != nil {
+			return fmt.Errorf("failed to link global addon %s: %v", name, err)
+		}
+		fmt.Printf("🔗 Linked global addon %s\n", name)
+	}
+
+	if len(cfg.GlobalAddons) > 0 {
+		lines := []string{"# Global addons linked by gdcli"}
+		for _, name := range cfg.GlobalAddons {
+			lines = append(lines, "/"+addons.Dir+"/"+name)
+		}
+		appendGitignore(lines)
+	}
+	return nil
+}
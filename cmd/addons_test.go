@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+)
+
+// inProject runs the test in a new project directory.
+func inProject(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func writeLock(t *testing.T, addons map[string]config.AddonLock) []byte {
+	t.Helper()
+	if err := config.SaveLock(&config.GodotLock{Addons: addons}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(config.LockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestInstallAddonsFrozenDoesNotWriteLock(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies map[string]string
+		locked       map[string]config.AddonLock
+		message      string
+	}{
+		{
+			name:    "pinned addon removed from the config",
+			locked:  map[string]config.AddonLock{"gut": {Source: "assetlib:1709", Version: "9.3.0"}},
+			message: "addon gut is pinned",
+		},
+		{
+			name:         "addon not pinned",
+			dependencies: map[string]string{"gut": "assetlib:1709"},
+			message:      "addon gut (assetlib:1709) is not pinned",
+		},
+		{
+			name:         "addon pinned from another source",
+			dependencies: map[string]string{"gut": "assetlib:1710"},
+			locked:       map[string]config.AddonLock{"gut": {Source: "assetlib:1709", Version: "9.3.0"}},
+			message:      "addon gut (assetlib:1710) is not pinned",
+		},
+	}
+
+	for _, tt := range tests {
+		inProject(t)
+		before := writeLock(t, tt.locked)
+
+		err := installAddons(&config.GodotConfig{Dependencies: tt.dependencies}, true)
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: installAddons() = %v, want an error about %q", tt.name, err, tt.message)
+		}
+		if after, _ := os.ReadFile(config.LockFile); string(after) != string(before) {
+			t.Errorf("%s: %s was written:\n%s", tt.name, config.LockFile, after)
+		}
+	}
+}
+
+func TestInstallAddonsForgetsRemovedAddons(t *testing.T) {
+	inProject(t)
+	writeLock(t, map[string]config.AddonLock{"gut": {Source: "assetlib:1709", Version: "9.3.0"}})
+
+	if err := installAddons(&config.GodotConfig{}, false); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := config.LoadLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lock.Addons["gut"]; ok {
+		t.Errorf("removed addon still pinned: %+v", lock.Addons)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"runtime"
+
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func addArchFlag(cmd *cobra.Command) {
+	cmd.Flags().String("arch", "", "Architecture to select versions for, e.g. x86_64 or arm64 (defaults to this system's)")
+}
+
+// setTargetArch selects versions for the architecture given with --arch.
+func setTargetArch(cmd *cobra.Command) error {
+	arch, _ := cmd.Flags().GetString("arch")
+	if arch == "" {
+		return nil
+	}
+
+	goarch, err := core.ParseArch(arch)
+	if err != nil {
+		return err
+	}
+	core.TargetArch = goarch
+	return nil
+}
+
+// platformName describes the platform versions are selected for, e.g.
+// "linux/arm64".
+func platformName() string {
+	return runtime.GOOS + "/" + core.TargetArch
+}
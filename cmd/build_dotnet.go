@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/dotnet"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(buildDotNetCmd())
+}
+
+func buildDotNetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build-dotnet",
+		Short: "Build the C# project of a Mono (.NET) project",
+		Long: `Build the C# project of a Mono (.NET) project with 'dotnet build', e.g. to
+catch compile errors before opening the editor or exporting. The .csproj and
+.sln are generated first if they are missing.
+Examples:
+  gdcli build-dotnet
+  gdcli build-dotnet --configuration ExportRelease`,
+		Args: cobra.NoArgs,
+		Run:  runBuildDotNet,
+	}
+	cmd.Flags().StringP("configuration", "c", "Debug", "Build configuration: "+strings.Join(dotnet.Configurations, ", "))
+	return cmd
+}
+
+func runBuildDotNet(cmd *cobra.Command, args []string) {
+	configuration, _ := cmd.Flags().GetString("configuration")
+	if !slices.Contains(dotnet.Configurations, configuration) {
+		fmt.Printf("❌ Unknown configuration '%s', use one of %s\n", configuration, strings.Join(dotnet.Configurations, ", "))
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println("❌ No config found")
+		fmt.Println("💡 First create a project with: gdcli init")
+		os.Exit(1)
+	}
+	if !cfg.IsDotNet {
+		fmt.Println("❌ Not a Mono (.NET) project, is_dotnet is not set in gdproj.json")
+		os.Exit(1)
+	}
+
+	sdkVersion, err := engineSdkVersion()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("💡 Run 'gdcli install' to install the required version")
+		os.Exit(1)
+	}
+
+	csproj, err := dotnet.FindProject(".")
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if csproj == "" {
+		if !generateDotNetProject(cfg.ProjectName) {
+			os.Exit(1)
+		}
+		if csproj, err = dotnet.FindProject("."); err != nil || csproj == "" {
+			fmt.Println("❌ No C# project found")
+			os.Exit(1)
+		}
+	} else if projectSdk, err := dotnet.ProjectSdkVersion(csproj); err == nil && projectSdk != sdkVersion {
+		fmt.Printf("⚠️ %s uses %s %s, the engine ships %s\n", csproj, dotnet.SdkPackage, projectSdk, sdkVersion)
+	}
+
+	sdk, err := dotnet.CheckSDK(sdkVersion)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🔨 Building %s (%s) with .NET SDK %s...\n", csproj, configuration, sdk)
+	if err := dotnet.Build(csproj, configuration); err != nil {
+		fmt.Printf("❌ Build failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Built %s\n", csproj)
+}
+
+// engineSdkVersion returns the Godot.NET.Sdk version of the engine linked
+// to the project.
+func engineSdkVersion() (string, error) {
+	engine, err := core.LoadProjectEngine()
+	if err != nil {
+		return "", fmt.Errorf("no engine linked to the project: %v", err)
+	}
+	return dotnet.SdkVersion(engine)
+}
+
+// generateDotNetProject writes the .csproj and .sln of a Mono project for the
+// Godot.NET.Sdk of its engine, unless it already has a C# project. It
+// reports whether the project has one afterwards.
+func generateDotNetProject(projectName string) bool {
+	if csproj, err := dotnet.FindProject("."); err != nil || csproj != "" {
+		return err == nil
+	}
+
+	sdkVersion, err := engineSdkVersion()
+	if err != nil {
+		fmt.Printf("⚠️ Cannot generate the C# project: %v\n", err)
+		return false
+	}
+
+	created, err := dotnet.GenerateProject(projectName, sdkVersion)
+	for _, file := range created {
+		fmt.Printf("📝 Created %s\n", file)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Failed to generate the C# project: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// buildDotNetProject builds the C# project of a Mono project in the
+// configuration, as 'gdcli build-dotnet' does, so compile errors surface
+// before the engine runs the project. It returns the built .csproj, which is
+// empty if the project is not a Mono project or has no C# project yet.
+func buildDotNetProject(configuration string) (string, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil || !cfg.IsDotNet {
+		return "", nil
+	}
+	csproj, err := dotnet.FindProject(".")
+	if err != nil || csproj == "" {
+		return "", err
+	}
+
+	sdkVersion, err := engineSdkVersion()
+	if err != nil {
+		return csproj, err
+	}
+	sdk, err := dotnet.CheckSDK(sdkVersion)
+	if err != nil {
+		return csproj, err
+	}
+
+	fmt.Printf("🔨 Building %s (%s) with .NET SDK %s...\n", csproj, configuration, sdk)
+	if err := dotnet.Build(csproj, configuration); err != nil {
+		return csproj, fmt.Errorf("build of %s failed: %v", csproj, err)
+	}
+	return csproj, nil
+}
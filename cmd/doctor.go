@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/dotnet"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(doctorCmd())
+}
+
+func doctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the project's setup",
+		Long: `Check that the project's engine is installed and, for Mono (.NET) projects,
+that the C# project and a .NET SDK building it are present.
+Examples:
+  gdcli doctor`,
+		Args: cobra.NoArgs,
+		Run:  runDoctor,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println("❌ No config found")
+		fmt.Println("💡 First create a project with: gdcli init")
+		os.Exit(1)
+	}
+
+	failed := 0
+	check := func(ok bool, message, hint string) {
+		if ok {
+			fmt.Printf("✅ %s\n", message)
+			return
+		}
+		fmt.Printf("❌ %s\n", message)
+		if hint != "" {
+			fmt.Printf("   💡 %s\n", hint)
+		}
+		failed++
+	}
+
+	enginePath, err := core.ProjectEnginePath()
+	if err == nil {
+		_, err = os.Stat(enginePath)
+	}
+	if err != nil {
+		check(false, fmt.Sprintf("Engine %s not installed: %v", cfg.EngineVersion, err), "Run 'gdcli install' to install it")
+	} else {
+		check(true, fmt.Sprintf("Engine installed: %s", enginePath), "")
+	}
+
+	if cfg.IsDotNet {
+		checkDotNet(check)
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%d problem(s) found\n", failed)
+		os.Exit(1)
+	}
+	fmt.Println("\nNo problems found")
+}
+
+// checkDotNet checks the C# project and the .NET SDK of a Mono project. A
+// project using another Godot.NET.Sdk than the engine still builds and is
+// only a warning.
+func checkDotNet(check func(ok bool, message, hint string)) {
+	sdkVersion, err := engineSdkVersion()
+	if err != nil {
+		check(false, fmt.Sprintf("Cannot tell the %s version: %v", dotnet.SdkPackage, err), "")
+		return
+	}
+
+	if sdk, err := dotnet.CheckSDK(sdkVersion); err != nil {
+		check(false, err.Error(), "")
+	} else {
+		check(true, fmt.Sprintf(".NET SDK %s installed (%s %s requires %d or newer)", sdk, dotnet.SdkPackage, sdkVersion, dotnet.RequiredSDKMajor(sdkVersion)), "")
+	}
+
+	csproj, err := dotnet.FindProject(".")
+	switch {
+	case err != nil:
+		check(false, err.Error(), "Keep a single .csproj in the project directory")
+	case csproj == "":
+		check(false, "No C# project found", "Generate it with: gdcli build-dotnet")
+	default:
+		projectSdk, err := dotnet.ProjectSdkVersion(csproj)
+		switch {
+		case err != nil:
+			check(false, err.Error(), "")
+		case projectSdk != sdkVersion:
+			fmt.Printf("⚠️ %s uses %s %s, the engine ships %s\n", csproj, dotnet.SdkPackage, projectSdk, sdkVersion)
+			fmt.Printf("   💡 Update the Sdk attribute in %s to %s/%s\n", csproj, dotnet.SdkPackage, sdkVersion)
+		default:
+			check(true, fmt.Sprintf("C# project %s uses %s %s", csproj, dotnet.SdkPackage, sdkVersion), "")
+		}
+	}
+}
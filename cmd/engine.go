@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/IgorBayerl/gdcli/internal/build"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(engineCmd())
+}
+
+func engineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "engine",
+		Short: "Manage custom engine builds",
+		Long: `Register custom engine builds, such as GodotSteam, to use them like official
+Godot versions. Projects select a custom engine by setting engine_version
+in gdproj.json to its name.`,
+	}
+	cmd.AddCommand(engineAddCmd(), engineBuildCmd(), engineListCmd(), engineRemoveCmd())
+	return cmd
+}
+
+func engineAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Register a custom engine build",
+		Long: `Register a custom engine from a zip archive or a local directory or
+executable.
+Examples:
+  gdcli engine add godotsteam-4.3 --url https://example.com/godotsteam-4.3-linux.zip --version 4.3.0
+  gdcli engine add my-godot --path ~/godot/bin/godot.linuxbsd.editor.x86_64
+  gdcli engine add my-godot-mono --path ~/godot/bin --mono`,
+		Args: cobra.ExactArgs(1),
+		Run:  runEngineAdd,
+	}
+	cmd.Flags().String("url", "", "URL of a zip archive with the engine")
+	cmd.Flags().String("path", "", "Local engine directory or executable")
+	cmd.Flags().String("version", "", "Godot version the engine is based on, e.g. 4.3.0")
+	cmd.Flags().String("sha512", "", "Expected SHA-512 checksum of the archive")
+	cmd.Flags().Bool("mono", false, "The engine is a Mono (.NET) build")
+	return cmd
+}
+
+func runEngineAdd(cmd *cobra.Command, args []string) {
+	url, _ := cmd.Flags().GetString("url")
+	path, _ := cmd.Flags().GetString("path")
+	version, _ := cmd.Flags().GetString("version")
+	sha512, _ := cmd.Flags().GetString("sha512")
+	mono, _ := cmd.Flags().GetBool("mono")
+	name := args[0]
+
+	if url != "" && path != "" {
+		fmt.Println("❌ --url and --path cannot be used together")
+		os.Exit(1)
+	}
+	if url == "" && path == "" {
+		fmt.Println("❌ Either --url or --path is required")
+		os.Exit(1)
+	}
+
+	engine, err := core.NewCustomEngine(name, url, path, version, sha512, mono)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	_, replaced := core.FindCustomEngine(name)
+	if err := core.AddCustomEngine(engine); err != nil {
+		fmt.Printf("❌ Error registering engine: %v\n", err)
+		os.Exit(1)
+	}
+
+	if replaced {
+		fmt.Printf("✅ Updated custom engine %s\n", name)
+	} else {
+		fmt.Printf("✅ Registered custom engine %s\n", name)
+	}
+	fmt.Printf("💡 Use it in a project with: gdcli install %s\n", name)
+	fmt.Printf("   or set \"engine_version\": \"%s\" in gdproj.json\n", name)
+}
+
+func engineBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Build the engine from source",
+		Long: `Build the editor from source with scons and register it as a custom engine
+named after the commit, e.g. "godot-1a2b3c4d5e".
+Examples:
+  gdcli engine build --source https://github.com/godotengine/godot.git --ref 4.3-stable
+  gdcli engine build --source https://github.com/me/godot.git --ref 1a2b3c4 --name studio-godot
+  gdcli engine build --source ~/src/godot --scons-args "dev_build=yes"
+  gdcli engine build --source ~/src/godot --mono`,
+		Args: cobra.NoArgs,
+		Run:  runEngineBuild,
+	}
+	cmd.Flags().String("source", "", "Git repository URL or local Godot source directory")
+	cmd.Flags().String("ref", "", "Branch, tag or commit to build")
+	cmd.Flags().String("name", "", "Name of the custom engine, defaults to godot-<commit>")
+	cmd.Flags().String("scons-args", "", "Extra arguments passed to scons")
+	cmd.Flags().Bool("mono", false, "Build with C# support")
+	cmd.MarkFlagRequired("source")
+	return cmd
+}
+
+func runEngineBuild(cmd *cobra.Command, args []string) {
+	src, _ := cmd.Flags().GetString("source")
+	ref, _ := cmd.Flags().GetString("ref")
+	name, _ := cmd.Flags().GetString("name")
+	sconsArgs, _ := cmd.Flags().GetString("scons-args")
+	mono, _ := cmd.Flags().GetBool("mono")
+
+	opts := build.Options{
+		Source:    src,
+		Ref:       ref,
+		Name:      name,
+		SconsArgs: strings.Fields(sconsArgs),
+		DotNet:    mono,
+	}
+
+	fmt.Printf("🔨 Building Godot from %s...\n", src)
+	engine, err := build.Build(opts)
+	if err != nil {
+		fmt.Printf("❌ Build failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Built %s", engine.Custom)
+	if engine.Commit != "" {
+		fmt.Printf(" from commit %s", engine.Commit)
+	}
+	fmt.Println()
+	fmt.Printf("💡 Use it in a project with: gdcli install %s\n", engine.Custom)
+	fmt.Printf("   or set \"engine_version\": \"%s\" in gdproj.json\n", engine.Custom)
+}
+
+func engineListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List registered custom engines",
+		Run:   runEngineList,
+	}
+}
+
+func runEngineList(cmd *cobra.Command, args []string) {
+	engines, err := core.CustomEngines()
+	if err != nil {
+		fmt.Printf("❌ Error reading custom engines: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(engines) == 0 {
+		fmt.Println("No custom engines registered")
+		fmt.Println("💡 Register one with: gdcli engine add <name> --url <zip> | --path <dir|binary>")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tVARIANT\tINSTALLED\tSOURCE")
+	for _, e := range engines {
+		source := e.URL
+		if e.Path != "" {
+			source = e.Path
+		}
+		if e.Commit != "" {
+			source = "built from " + e.Commit
+		}
+		version := e.Version
+		if version == "" {
+			version = "-"
+		}
+		installed := "no"
+		if core.IsEngineInstalled(e) {
+			installed = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Custom, version, variantName(e.DotNet), installed, source)
+	}
+	w.Flush()
+}
+
+func engineRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove the registration of a custom engine",
+		Long: `Remove the registration of a custom engine. An installed copy stays in the
+engine store until removed with 'gdcli uninstall <name>'.`,
+		Args: cobra.ExactArgs(1),
+		Run:  runEngineRemove,
+	}
+}
+
+func runEngineRemove(cmd *cobra.Command, args []string) {
+	if err := core.RemoveCustomEngine(args[0]); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Removed custom engine %s\n", args[0])
+}
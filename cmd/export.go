@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/godot"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd())
+}
+
+func exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [preset]",
+		Short: "Export the project using an export preset",
+		Long: `Export the project with the project's engine, using a preset from
+export_presets.cfg. Without a preset, the available presets are listed.
+Examples:
+  gdcli export                                  # List presets
+  gdcli export "Windows Desktop"                # Release export to the preset's path
+  gdcli export Linux --debug --output build/linux/game.x86_64
+  gdcli export --all                            # Export every preset`,
+		Args: cobra.MaximumNArgs(1),
+		Run:  runExport,
+	}
+	cmd.Flags().Bool("release", false, "Export a release build (default)")
+	cmd.Flags().Bool("debug", false, "Export a debug build")
+	cmd.Flags().StringP("output", "o", "", "Output file, defaults to the preset's export path")
+	cmd.Flags().Bool("all", false, "Export every preset")
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	release, _ := cmd.Flags().GetBool("release")
+	debug, _ := cmd.Flags().GetBool("debug")
+	output, _ := cmd.Flags().GetString("output")
+	all, _ := cmd.Flags().GetBool("all")
+
+	if release && debug {
+		fmt.Println("❌ --release and --debug cannot be used together")
+		os.Exit(1)
+	}
+	if all && (len(args) > 0 || output != "") {
+		fmt.Println("❌ --all exports every preset to its own path and cannot be used with a preset or --output")
+		os.Exit(1)
+	}
+
+	presets, err := godot.LoadExportPresets()
+	if err != nil {
+		fmt.Printf("❌ Error reading %s: %v\n", godot.ExportPresetsFile, err)
+		fmt.Println("💡 Create export presets in the editor under Project > Export")
+		os.Exit(1)
+	}
+
+	if len(args) == 0 && !all {
+		listExportPresets(presets)
+		return
+	}
+
+	var targets []godot.ExportPreset
+	if all {
+		targets = presets
+	} else {
+		preset, err := godot.FindExportPreset(presets, args[0])
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if output != "" {
+			preset.ExportPath = output
+		}
+		targets = []godot.ExportPreset{preset}
+	}
+
+	godotPath, err := core.ProjectConsolePath()
+	if err != nil {
+		fmt.Printf("❌ Godot executable not found: %v\n", err)
+		fmt.Println("💡 Run 'gdcli install' to install the required version")
+		os.Exit(1)
+	}
+
+	// Mono projects fail to export, or export stale assemblies, when their
+	// C# code does not build
+	configuration := "ExportRelease"
+	if debug {
+		configuration = "ExportDebug"
+	}
+	if _, err := buildDotNetProject(configuration); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Printf("💡 Check the C# build with 'gdcli build-dotnet --configuration %s'\n", configuration)
+		os.Exit(1)
+	}
+
+	if err := ensureExportTemplates(); err != nil {
+		fmt.Printf("❌ Export templates installation failed: %v\n", err)
+		fmt.Println("💡 Retry with 'gdcli install --export-templates'")
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, preset := range targets {
+		if err := exportPreset(godotPath, preset, debug); err != nil {
+			fmt.Printf("❌ Export of '%s' failed: %v\n", preset.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ Exported '%s' to %s\n", preset.Name, preset.ExportPath)
+	}
+
+	if failed > 0 {
+		if len(targets) > 1 {
+			fmt.Printf("❌ %d of %d exports failed\n", failed, len(targets))
+		}
+		os.Exit(1)
+	}
+}
+
+func listExportPresets(presets []godot.ExportPreset) {
+	if len(presets) == 0 {
+		fmt.Println("No export presets defined")
+		fmt.Println("💡 Create export presets in the editor under Project > Export")
+		return
+	}
+
+	fmt.Println("Export presets:")
+	for _, p := range presets {
+		path := p.ExportPath
+		if path == "" {
+			path = "no export path, use --output"
+		}
+		fmt.Printf("  %s (%s) -> %s\n", p.Name, p.Platform, path)
+	}
+}
+
+// ensureExportTemplates installs the export templates for the project's
+// engine if they are missing. Engines kept in the project's dependencies
+// directory by older gdcli versions have no known version and are skipped.
+// Custom engines and source builds have no templates to download, so only a
+// warning is shown and the export is left to report missing templates.
+func ensureExportTemplates() error {
+	engine, err := core.LoadProjectEngine()
+	if err != nil {
+		return nil
+	}
+	if core.ExportTemplatesInstalled(engine.Version) {
+		return nil
+	}
+
+	if !core.ExportTemplatesPublished(engine.Version) {
+		dir, err := core.ExportTemplatesDir(engine.Version)
+		if err != nil {
+			fmt.Printf("⚠️  Cannot check the export templates of %s: %v\n", engine.Version.DisplayName, err)
+		} else {
+			fmt.Printf("⚠️  No export templates for %s found in %s\n", engine.Version.DisplayName, dir)
+		}
+		fmt.Println("💡 Custom engines need their export templates installed manually, e.g. from the editor's Export Template Manager")
+		return nil
+	}
+
+	fmt.Printf("📦 Installing export templates for %s...\n", engine.Version.DisplayName)
+	return core.InstallExportTemplates(engine.Version)
+}
+
+// exportPreset runs a headless export of the preset to its export path.
+func exportPreset(godotPath string, preset godot.ExportPreset, debug bool) error {
+	if preset.ExportPath == "" {
+		return fmt.Errorf("the preset has no export path, set one in the editor or use --output")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(preset.ExportPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	mode := "--export-release"
+	if debug {
+		mode = "--export-debug"
+	}
+
+	fmt.Printf("🚀 Exporting '%s' (%s)...\n", preset.Name, mode[len("--export-"):])
+	exportCmd := exec.Command(godotPath, "--headless", "--path", ".", mode, preset.Name, preset.ExportPath)
+	exportCmd.Stdout = os.Stdout
+	exportCmd.Stderr = os.Stderr
+	if err := exportCmd.Run(); err != nil {
+		return err
+	}
+
+	// Godot may exit successfully without writing anything, e.g. when the
+	// preset is misconfigured.
+	if _, err := os.Stat(preset.ExportPath); err != nil {
+		return fmt.Errorf("no output was written to %s", preset.ExportPath)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ Examples:
   gdcli init      Initialize new project
   gdcli install   Install Godot engine
   gdcli open      Launch Godot editor
+  gdcli list      List installed Godot versions
 `
 
 func init() {
@@ -14,7 +14,11 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/IgorBayerl/gdcli/internal/config"
 	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/dotnet"
+	"github.com/IgorBayerl/gdcli/internal/semver"
+	"github.com/IgorBayerl/gdcli/internal/templates"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func init() {
@@ -22,114 +26,333 @@ func init() {
 }
 
 func initCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize new Godot project",
-		Run:   runInit,
+		Long: `Initialize a new Godot project in the current directory.
+Prompts for anything not given with flags. When stdin is not a terminal or
+--yes is given, defaults are used instead of prompting.
+Examples:
+  gdcli init
+  gdcli init --name MyGame --engine 4.3 --mono --yes --no-open
+  gdcli init --template platformer
+  gdcli init --template ../studio-starter --var company=Acme
+  gdcli init --template https://example.com/starter.zip
+  gdcli init --template https://github.com/studio/starter.git#v2`,
+		Run: runInit,
 	}
+	cmd.Flags().String("name", "", "Project name (defaults to the directory name)")
+	cmd.Flags().String("engine", "", "Godot version or constraint, e.g. 4.3.0 or ~4.3 (defaults to the newest stable)")
+	cmd.Flags().Bool("mono", false, "Use the Mono (.NET) variant")
+	cmd.Flags().BoolP("yes", "y", false, "Use defaults instead of prompting")
+	cmd.Flags().Bool("no-open", false, "Do not open the editor afterwards")
+	cmd.Flags().String("template", "", "Project template: 2d, 3d, platformer, menu, a directory, an archive URL or a git URL")
+	cmd.Flags().StringArray("var", nil, "Template variable as key=value, may be repeated")
+	cmd.Flags().Bool("force", false, "Initialize again and override an existing project.godot")
+	addArchFlag(cmd)
+	return cmd
 }
 
 func runInit(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("name")
+	engine, _ := cmd.Flags().GetString("engine")
+	mono, _ := cmd.Flags().GetBool("mono")
+	yes, _ := cmd.Flags().GetBool("yes")
+	noOpen, _ := cmd.Flags().GetBool("no-open")
+	force, _ := cmd.Flags().GetBool("force")
+	templateName, _ := cmd.Flags().GetString("template")
+	varFlags, _ := cmd.Flags().GetStringArray("var")
+
+	if err := setTargetArch(cmd); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Prompts need a terminal, scripts and CI get the defaults
+	interactive := !yes && term.IsTerminal(int(os.Stdin.Fd()))
+
 	// Check if config already exists
-	if _, err := os.Stat("gdproj.json"); err == nil {
+	if _, err := os.Stat("gdproj.json"); err == nil && !force {
 		fmt.Println("Project already initialized. Run 'gdcli install' to install dependencies.")
-		return
-	} else if !os.IsNotExist(err) {
+		fmt.Println("Use --force to initialize it again.")
+		os.Exit(1)
+	} else if err != nil && !os.IsNotExist(err) {
 		fmt.Printf("Error checking for existing config: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	// Get current directory name
 	wd, err := os.Getwd()
 	if err != nil {
 		fmt.Printf("Error getting current directory: %v\n", err)
-		return
+		os.Exit(1)
+	}
+	projectName := name
+	if projectName == "" {
+		projectName = filepath.Base(wd)
+	}
+
+	if err := core.RefreshManifest(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
 	}
-	defaultProjectName := filepath.Base(wd)
 
+	// Only stable releases are offered, pre-releases can be installed with 'gdcli install <version>'
 	var versionOptions []string
-	currentOS := runtime.GOOS
 	for _, v := range core.VersionManifest {
-		if v.OS == currentOS {
+		if v.ForPlatform() && v.Stable() && (!cmd.Flags().Changed("mono") || v.DotNet == mono) {
 			versionOptions = append(versionOptions, v.DisplayName)
 		}
 	}
-
-	qs := []*survey.Question{
-		{
-			Name: "projectName",
-			Prompt: &survey.Input{
-				Message: "Project name:",
-				Default: defaultProjectName,
-			},
-		},
-		{
-			Name: "version",
-			Prompt: &survey.Select{
-				Message: "Select Godot version:",
-				Options: versionOptions,
-				Default: versionOptions[0],
-			},
-		},
+	customEngines, _ := core.CustomEngines()
+	for _, v := range customEngines {
+		if v.OS == runtime.GOOS && (!cmd.Flags().Changed("mono") || v.DotNet == mono) {
+			versionOptions = append(versionOptions, v.DisplayName)
+		}
 	}
 
-	answers := struct {
-		ProjectName string
-		Version     string
-	}{}
+	if engine == "" && len(versionOptions) == 0 {
+		fmt.Printf("No Godot versions available for %s\n", platformName())
+		os.Exit(1)
+	}
 
-	if err := survey.Ask(qs, &answers); err != nil {
-		fmt.Printf("Error during survey: %v\n", err)
-		return
+	if interactive && name == "" {
+		prompt := &survey.Input{
+			Message: "Project name:",
+			Default: projectName,
+		}
+		if err := survey.AskOne(prompt, &projectName); err != nil {
+			fmt.Printf("Error during survey: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	selected, err := core.GetVersionByIdentifier(answers.Version)
+	var selected core.GodotVersion
+	var engineVersion string
+	switch {
+	case engine != "":
+		selected, engineVersion, err = resolveInitEngine(engine, mono)
+	case interactive:
+		prompt := &survey.Select{
+			Message: "Select Godot version:",
+			Options: versionOptions,
+			Default: versionOptions[0],
+		}
+		var answer string
+		if err := survey.AskOne(prompt, &answer); err != nil {
+			fmt.Printf("Error during survey: %v\n", err)
+			os.Exit(1)
+		}
+		selected, err = core.GetVersionByIdentifier(answer, mono)
+		engineVersion = selected.ConfigVersion()
+	default:
+		// Newest stable version of the chosen variant
+		selected, err = core.ResolveVersion("*", mono)
+		engineVersion = selected.Version
+	}
 	if err != nil {
 		fmt.Printf("Version selection error: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
-	if err := config.CreateConfig(selected.Version, answers.ProjectName, selected.DotNet); err != nil {
+	if interactive && templateName == "" {
+		templateName, err = askTemplate()
+		if err != nil {
+			fmt.Printf("Error during survey: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var tmpl *templates.Template
+	var templateVars map[string]string
+	if templateName != "" && templateName != "none" {
+		tmpl, err = templates.Resolve(templateName)
+		// Custom engines registered without a base version cannot be checked
+		if err == nil && selected.Version != "" {
+			err = tmpl.CheckEngine(selected.Version)
+		}
+		if err == nil {
+			templateVars, err = templateVariables(tmpl, varFlags, interactive)
+		}
+		if err != nil {
+			fmt.Printf("Template error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := config.CreateConfig(engineVersion, projectName, selected.DotNet); err != nil {
 		fmt.Printf("Error creating config: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	fmt.Printf("Installing Godot %s...\n", selected.DisplayName)
 	if err := core.InstallGodotVersion(selected); err != nil {
 		fmt.Printf("Installation failed: %v\n", err)
-		return
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err == nil {
+		err = writeEngineLock(cfg, selected)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to write %s: %v\n", config.LockFile, err)
 	}
 
 	// Check that `project.godot` does not exist, so as to not override on existing project
 	if _, err := os.Stat("project.godot"); os.IsNotExist(err) {
 		fmt.Printf("Did not find a 'project.godot' file, creating new Godot project...\n")
-		if err := createGodotProjectFile(answers.ProjectName); err != nil {
+		if err := createProject(tmpl, templateVars, projectName, selected, false); err != nil {
 			fmt.Printf("Error creating project file: %v\n", err)
-			return
+			os.Exit(1)
 		}
 	} else {
 		fmt.Printf("Found existing 'project.godot' file.\n")
-		prompt := &survey.Confirm {
-			Message: "Override Existing project.godot?",
-			Default: false,
-		}
 
-		var overrideProject bool;
-		if err := survey.AskOne(prompt, &overrideProject); err != nil {
-			fmt.Printf("Error during survey: %v\n", err)
-			return
+		overrideProject := force
+		if interactive && !force {
+			prompt := &survey.Confirm{
+				Message: "Override Existing project.godot?",
+				Default: false,
+			}
+
+			if err := survey.AskOne(prompt, &overrideProject); err != nil {
+				fmt.Printf("Error during survey: %v\n", err)
+				os.Exit(1)
+			}
 		}
 
 		if overrideProject {
-			if err := createGodotProjectFile(answers.ProjectName); err != nil {
+			if err := createProject(tmpl, templateVars, projectName, selected, true); err != nil {
 				fmt.Printf("Error creating project file: %v\n", err)
-				return
+				os.Exit(1)
+			}
+		}
+	}
+
+	// The editor builds C# scripts with the .NET SDK, which gdcli does not install
+	if selected.DotNet && generateDotNetProject(projectName) {
+		if sdkVersion, err := engineSdkVersion(); err == nil {
+			if _, err := dotnet.CheckSDK(sdkVersion); err != nil {
+				fmt.Printf("⚠️ %v\n", err)
 			}
 		}
 	}
 
 	updateGitignore()
-	runOpen(cmd, args)
+	if !noOpen {
+		runOpen(cmd, args)
+	}
+}
+
+// resolveInitEngine resolves the --engine flag, either a constraint such as
+// "~4.3", which is kept in gdproj.json as given, or a version name such as
+// "4.3.0 (Mono)".
+func resolveInitEngine(engine string, mono bool) (core.GodotVersion, string, error) {
+	if v, err := core.ResolveVersion(engine, mono); err == nil {
+		return v, engine, nil
+	}
+
+	v, err := core.GetVersionByIdentifier(engine, mono)
+	if err != nil {
+		return core.GodotVersion{}, "", err
+	}
+	return v, v.ConfigVersion(), nil
+}
+
+// askTemplate prompts for one of the built-in templates, or "none" for an
+// empty project.
+func askTemplate() (string, error) {
+	builtin, err := templates.Builtin()
+	if err != nil {
+		return "", err
+	}
+
+	options := []string{"none"}
+	descriptions := map[string]string{"none": "Empty project"}
+	for _, t := range builtin {
+		options = append(options, t.Name)
+		descriptions[t.Name] = t.Description
+	}
+
+	prompt := &survey.Select{
+		Message: "Select project template:",
+		Options: options,
+		Default: "none",
+		Description: func(value string, index int) string {
+			return descriptions[value]
+		},
+	}
+
+	var answer string
+	err = survey.AskOne(prompt, &answer)
+	return answer, err
+}
+
+// templateVariables collects the values of the variables declared by the
+// template from --var flags, prompting for the rest when interactive.
+func templateVariables(tmpl *templates.Template, varFlags []string, interactive bool) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, v := range varFlags {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var '%s', expected key=value", v)
+		}
+		vars[key] = value
+	}
+
+	for _, v := range tmpl.Variables {
+		if _, ok := vars[v.Name]; ok {
+			continue
+		}
+
+		if !interactive {
+			if v.Default == "" {
+				return nil, fmt.Errorf("missing value for template variable '%s', pass it with --var %s=<value>", v.Name, v.Name)
+			}
+			vars[v.Name] = v.Default
+			continue
+		}
+
+		message := v.Description
+		if message == "" {
+			message = v.Name
+		}
+		var value string
+		prompt := &survey.Input{
+			Message: message + ":",
+			Default: v.Default,
+		}
+		if err := survey.AskOne(prompt, &value); err != nil {
+			return nil, err
+		}
+		vars[v.Name] = value
+	}
+
+	return vars, nil
+}
+
+// createProject writes the project files from the template, or a minimal
+// project.godot without one.
+func createProject(tmpl *templates.Template, templateVars map[string]string, projectName string, version core.GodotVersion, overwrite bool) error {
+	if tmpl == nil {
+		return createGodotProjectFile(projectName)
+	}
+
+	engineVersion := version.Version
+	if v, err := semver.Parse(version.Version); err == nil {
+		engineVersion = fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+
+	vars := map[string]string{
+		"project_name":   projectName,
+		"engine_version": engineVersion,
+	}
+	for key, value := range templateVars {
+		vars[key] = value
+	}
+
+	fmt.Printf("Applying template %s...\n", tmpl.Name)
+	return tmpl.Apply(".", vars, overwrite)
 }
 
 func createGodotProjectFile(projectName string) error {
@@ -147,7 +370,7 @@ environment/default_environment=""
 }
 
 func updateGitignore() {
-	linesToAdd := []string{
+	appendGitignore([]string{
 		"# Godot CLI",
 		"dependencies/*",
 		"",
@@ -157,7 +380,6 @@ func updateGitignore() {
 		"# Godot-specific ignores",
 		".import/",
 		"export.cfg",
-		"export_presets.cfg",
 		"",
 		"# Imported translations (automatically generated from CSV files)",
 		"*.translation",
@@ -166,8 +388,12 @@ func updateGitignore() {
 		".mono/",
 		"data_*/",
 		"mono_crash.*.json",
-	}
+	})
+}
 
+// appendGitignore adds the lines missing from .gitignore, creating it if
+// needed.
+func appendGitignore(linesToAdd []string) {
 	gitignorePath := ".gitignore"
 	var existingLines map[string]bool = make(map[string]bool)
 
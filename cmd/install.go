@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"runtime"
+	"os"
 
 	"github.com/IgorBayerl/gdcli/internal/config"
 	"github.com/IgorBayerl/gdcli/internal/core"
@@ -14,71 +14,156 @@ func init() {
 }
 
 func installCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "install [version]",
 		Short: "Install Godot engine version",
 		Long: `Install a specific Godot version or use the version from config.
 Examples:
-  gdcli install 4.3.0-mono    # Install specific version
-  gdcli install               # Use version from gdproj.json`,
+  gdcli install 4.3.0         # Install specific version
+  gdcli install 4.3.0 --mono  # Install the Mono (.NET) variant of a version
+  gdcli install               # Use version and addons from gdproj.lock or gdproj.json
+  gdcli install --frozen-lockfile   # Fail if gdproj.lock is out of date (CI)
+  gdcli install --export-templates  # Also install the export templates
+  gdcli install --arch arm64        # Install the build for another architecture`,
 		Run: runInstall,
 	}
+	cmd.Flags().IntVar(&core.DefaultDownloadOptions.Retries, "retries", core.DefaultDownloadOptions.Retries, "Number of times to retry a failed download")
+	cmd.Flags().Bool("mono", false, "Install the Mono (.NET) variant of the given version")
+	cmd.Flags().Bool("export-templates", false, "Also install the export templates for the version")
+	cmd.Flags().Bool("frozen-lockfile", false, "Fail instead of resolving a new version when gdproj.lock is missing or out of date")
+	cmd.Flags().DurationVar(&core.DefaultDownloadOptions.StallTimeout, "stall-timeout", core.DefaultDownloadOptions.StallTimeout, "Retry a download that received no data for this long, 0 to wait forever")
+	addArchFlag(cmd)
+	return cmd
 }
 
 func runInstall(cmd *cobra.Command, args []string) {
+	frozen, _ := cmd.Flags().GetBool("frozen-lockfile")
+	exportTemplates, _ := cmd.Flags().GetBool("export-templates")
+	mono, _ := cmd.Flags().GetBool("mono")
+
+	if err := setTargetArch(cmd); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
 	var version core.GodotVersion
 	var err error
 
+	// Set when the version was resolved from gdproj.json rather than the lock file
+	var updateLock *config.GodotConfig
+
+	// Set when installing the project's dependencies from gdproj.json
+	var project *config.GodotConfig
+
+	if err := core.RefreshManifest(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+
+	if len(args) > 0 && frozen {
+		fmt.Println("❌ --frozen-lockfile installs the version from gdproj.lock and cannot be used with a version argument")
+		os.Exit(1)
+	}
+
 	if len(args) > 0 {
 		// Install specified version
-		version, err = core.GetVersionByIdentifier(args[0])
+		version, err = core.GetVersionByIdentifier(args[0], mono)
 		if err != nil {
 			fmt.Printf("❌ Version error: %v\n", err)
 			fmt.Println("💡 Available versions:")
-			currentOS := runtime.GOOS
 			for _, v := range core.VersionManifest {
-				if v.OS == currentOS {
+				if v.ForPlatform() {
 					fmt.Printf("  - %s\n", v.DisplayName)
 				}
 			}
-			return
+			os.Exit(1)
 		}
 	} else {
 		// Try to use config version
 		cfg, err := config.LoadConfig()
 		if err != nil {
+			if frozen {
+				fmt.Printf("❌ --frozen-lockfile: no config found: %v\n", err)
+				os.Exit(1)
+			}
 			fmt.Println("❌ No version specified and no config found")
 			fmt.Println("💡 First create a project with: gdcli init")
 			fmt.Println("   Or specify a version: gdcli install [version]")
-			return
+			os.Exit(1)
 		}
+		project = cfg
+
+		lock, lockErr := config.LoadLock()
+		lockMatches := lockErr == nil && lock.Engine.Matches(cfg)
 
-		// Find config version in manifest
-		found := false
-		currentOS := runtime.GOOS
-		for _, v := range core.VersionManifest {
-			if v.Version == cfg.EngineVersion && v.DotNet == cfg.IsDotNet && v.OS == currentOS {
-				version = v
-				found = true
-				break
+		if frozen && !lockMatches {
+			if lockErr != nil {
+				fmt.Printf("❌ --frozen-lockfile: cannot read %s: %v\n", config.LockFile, lockErr)
+			} else if lock.Engine == nil {
+				fmt.Printf("❌ --frozen-lockfile: %s has no engine pinned\n", config.LockFile)
+			} else {
+				fmt.Printf("❌ --frozen-lockfile: %s was resolved from %s (%s) but gdproj.json requires %s (%s)\n",
+					config.LockFile,
+					lock.Engine.EngineVersion, variantName(lock.Engine.IsDotNet),
+					cfg.EngineVersion, variantName(cfg.IsDotNet),
+				)
 			}
+			fmt.Println("💡 Run 'gdcli install' without --frozen-lockfile to update the lock file")
+			os.Exit(1)
 		}
 
-		if !found {
-			fmt.Printf("❌ Configured version %s (%s) not found\n",
-				cfg.EngineVersion,
-				map[bool]string{true: "Mono", false: "Standard"}[cfg.IsDotNet],
-			)
-			fmt.Println("💡 Update your config or install manually:")
-			fmt.Println("   gdcli install [version]")
-			return
+		if lockMatches {
+			version, err = lockedVersion(lock.Engine)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			// Find the newest manifest version matching the configured constraint
+			version, err = core.ResolveVersion(cfg.EngineVersion, cfg.IsDotNet)
+			if err != nil {
+				fmt.Printf("❌ Configured version %s (%s) not found: %v\n",
+					cfg.EngineVersion,
+					variantName(cfg.IsDotNet),
+					err,
+				)
+				// Custom engine names get their own hint with the error
+				if core.ValidateCustomName(cfg.EngineVersion) != nil {
+					fmt.Println("💡 Update your config or install manually:")
+					fmt.Println("   gdcli install [version]")
+				}
+				os.Exit(1)
+			}
+			updateLock = cfg
 		}
 	}
 
 	fmt.Printf("🚀 Installing %s...\n", version.DisplayName)
 	if err := core.InstallGodotVersion(version); err != nil {
 		fmt.Printf("❌ Installation failed: %v\n", err)
-		return
+		os.Exit(1)
+	}
+
+	if exportTemplates {
+		fmt.Printf("📦 Installing export templates for %s...\n", version.DisplayName)
+		if err := core.InstallExportTemplates(version); err != nil {
+			fmt.Printf("❌ Export templates installation failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if updateLock != nil {
+		if err := writeEngineLock(updateLock, version); err != nil {
+			fmt.Printf("⚠️  Failed to write %s: %v\n", config.LockFile, err)
+		} else {
+			fmt.Printf("🔒 Pinned %s in %s\n", version.DisplayName, config.LockFile)
+		}
+	}
+
+	if project != nil {
+		if err := installAddons(project, frozen); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	fmt.Printf("✅ Successfully installed %s\n", version.DisplayName)
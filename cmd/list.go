@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(listCmd())
+}
+
+func listCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List installed or available Godot versions",
+		Long: `List the Godot versions installed locally, or every version available for
+this platform with --remote.
+Examples:
+  gdcli list                       # Installed engines
+  gdcli list --remote              # Stable versions available for download
+  gdcli list --remote --mono --prerelease
+  gdcli list --remote --arch arm64 # Versions available for another architecture
+  gdcli list --json                # Machine readable output`,
+		Run: runList,
+	}
+	cmd.Flags().Bool("remote", false, "List versions available for download")
+	cmd.Flags().Bool("mono", false, "Only list Mono (.NET) versions")
+	cmd.Flags().Bool("standard", false, "Only list standard versions")
+	cmd.Flags().Bool("prerelease", false, "Include dev, beta and rc versions with --remote")
+	cmd.Flags().Bool("json", false, "Print the list as JSON")
+	addArchFlag(cmd)
+	return cmd
+}
+
+// listedEngine is an entry of 'gdcli list' output.
+type listedEngine struct {
+	core.GodotVersion
+	InstallPath string `json:"install_path,omitempty"` // Directory of the installed engine
+	Pinned      bool   `json:"pinned"`
+	Linked      bool   `json:"linked"`
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	remote, _ := cmd.Flags().GetBool("remote")
+	mono, _ := cmd.Flags().GetBool("mono")
+	standard, _ := cmd.Flags().GetBool("standard")
+	prerelease, _ := cmd.Flags().GetBool("prerelease")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	// JSON output is parsed by scripts, errors and warnings go to stderr
+	messages := os.Stdout
+	if asJSON {
+		messages = os.Stderr
+	}
+	fail := func(format string, a ...interface{}) {
+		fmt.Fprintf(messages, "❌ "+format+"\n", a...)
+		os.Exit(1)
+	}
+
+	if mono && standard {
+		fail("--mono and --standard cannot be used together")
+	}
+	if err := setTargetArch(cmd); err != nil {
+		fail("%v", err)
+	}
+
+	// The pinned version is only known inside a project
+	cfg, _ := config.LoadConfig()
+	pinned, pinnedByLock := pinnedVersion(cfg)
+
+	var entries []listedEngine
+	if remote {
+		if err := core.RefreshManifest(); err != nil {
+			fmt.Fprintf(messages, "⚠️  %v\n", err)
+		}
+
+		for _, v := range core.VersionManifest {
+			if !v.ForPlatform() || (!prerelease && !v.Stable()) {
+				continue
+			}
+			entries = append(entries, listedEngine{GodotVersion: v, Pinned: isPinned(pinned, v)})
+		}
+	} else {
+		var err error
+		entries, err = installedEngines(pinned)
+		if err != nil {
+			fail("Error reading installed engines: %v", err)
+		}
+	}
+
+	var filtered []listedEngine
+	for _, e := range entries {
+		if (mono && !e.DotNet) || (standard && e.DotNet) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	if asJSON {
+		if filtered == nil {
+			filtered = []listedEngine{}
+		}
+		data, err := json.MarshalIndent(filtered, "", "  ")
+		if err != nil {
+			fail("Error encoding list: %v", err)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(filtered) == 0 {
+		if remote {
+			fmt.Printf("No versions available for %s\n", platformName())
+		} else {
+			fmt.Println("No engines installed")
+			fmt.Println("💡 Install one with: gdcli install [version]")
+		}
+		return
+	}
+
+	for _, e := range filtered {
+		marker := " "
+		if e.Pinned {
+			marker = "*"
+		}
+
+		line := fmt.Sprintf("%s %s", marker, e.DisplayName)
+		if e.Arch != "" && e.Arch != runtime.GOARCH && e.Arch != core.UniversalArch {
+			line += fmt.Sprintf(" (%s)", e.Arch)
+		}
+		if e.Linked {
+			line += " [linked to this project]"
+		}
+		if e.InstallPath != "" {
+			line += fmt.Sprintf("  %s", e.InstallPath)
+		}
+		fmt.Println(line)
+	}
+
+	switch {
+	case pinnedByLock:
+		fmt.Printf("\n* pinned in %s\n", config.LockFile)
+	case pinned != nil:
+		fmt.Println("\n* selected by gdproj.json")
+	}
+}
+
+// installedEngines lists the engines in the store, followed by an engine kept
+// in the project's dependencies directory by older gdcli versions.
+func installedEngines(pinned *core.GodotVersion) ([]listedEngine, error) {
+	versions, err := core.InstalledEngines()
+	if err != nil {
+		return nil, err
+	}
+
+	linked, _ := core.LoadProjectEngine()
+
+	var entries []listedEngine
+	for _, v := range versions {
+		entries = append(entries, listedEngine{
+			GodotVersion: v,
+			InstallPath:  core.EngineDir(v),
+			Pinned:       isPinned(pinned, v),
+			Linked:       linked != nil && linked.Path == core.EngineDir(v),
+		})
+	}
+
+	legacyPath := filepath.Join(core.DependenciesDir, core.LegacyExecutable)
+	if _, err := os.Stat(legacyPath); err == nil && linked == nil {
+		entries = append(entries, listedEngine{
+			GodotVersion: core.GodotVersion{DisplayName: "unknown version (project dependencies)"},
+			InstallPath:  core.DependenciesDir,
+			Linked:       true,
+		})
+	}
+
+	return entries, nil
+}
+
+// pinnedVersion returns the build the project installs: the one recorded in
+// gdproj.lock, or without an up to date lock the newest version matching
+// gdproj.json. It is nil outside of a project.
+func pinnedVersion(cfg *config.GodotConfig) (pinned *core.GodotVersion, byLock bool) {
+	if cfg == nil {
+		return nil, false
+	}
+
+	if lock, err := config.LoadLock(); err == nil && lock.Engine.Matches(cfg) {
+		if v, err := lockedVersion(lock.Engine); err == nil {
+			return &v, true
+		}
+	}
+
+	if v, err := core.ResolveVersion(cfg.EngineVersion, cfg.IsDotNet); err == nil {
+		return &v, false
+	}
+	return nil, false
+}
+
+// isPinned reports whether v is the pinned build for the target platform.
+func isPinned(pinned *core.GodotVersion, v core.GodotVersion) bool {
+	if pinned == nil || !v.ForPlatform() || v.DotNet != pinned.DotNet || v.Custom != pinned.Custom {
+		return false
+	}
+	return v.Custom != "" || v.Version == pinned.Version && v.Tag == pinned.Tag
+}
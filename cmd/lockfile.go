@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+)
+
+func variantName(dotnet bool) string {
+	return map[bool]string{true: "Mono", false: "Standard"}[dotnet]
+}
+
+// writeEngineLock pins the version resolved from the config in gdproj.lock,
+// recording the download of the same build for every OS.
+func writeEngineLock(cfg *config.GodotConfig, version core.GodotVersion) error {
+	assets, err := core.ReleaseAssets(version)
+	if err != nil {
+		return err
+	}
+
+	lock, err := config.LoadLock()
+	if err != nil {
+		lock = &config.GodotLock{}
+	}
+
+	lock.Engine = &config.EngineLock{
+		EngineVersion: cfg.EngineVersion,
+		IsDotNet:      cfg.IsDotNet,
+		Version:       version.Version,
+		Tag:           version.Tag,
+		Custom:        version.Custom,
+	}
+	for _, a := range assets {
+		lock.Engine.Assets = append(lock.Engine.Assets, config.LockedAsset{
+			OS:     a.OS,
+			Arch:   a.Arch,
+			URL:    a.URL,
+			SHA512: a.SHA512,
+		})
+	}
+
+	return config.SaveLock(lock)
+}
+
+// lockedVersion returns the locked build for the platform. Custom engines
+// installed from a path have no locked build and must be registered on every
+// machine.
+func lockedVersion(lock *config.EngineLock) (core.GodotVersion, error) {
+	asset, ok := lock.Asset(runtime.GOOS, core.TargetArch)
+	if !ok && lock.Custom != "" {
+		if v, registered := core.FindCustomEngine(lock.Custom); registered {
+			return v, nil
+		}
+		return core.GodotVersion{}, core.CustomEngineMissingError(lock.Custom)
+	}
+	if !ok {
+		return core.GodotVersion{}, fmt.Errorf("%s has no %s build of %s, delete it to resolve the version again",
+			config.LockFile, platformName(), lock.Version)
+	}
+
+	displayName := fmt.Sprintf("%s (%s)", lock.Version, variantName(lock.IsDotNet))
+	if lock.Custom != "" {
+		displayName = lock.Custom
+	}
+
+	return core.GodotVersion{
+		DisplayName: displayName,
+		Version:     lock.Version,
+		Tag:         lock.Tag,
+		Custom:      lock.Custom,
+		DotNet:      lock.IsDotNet,
+		URL:         asset.URL,
+		SHA512:      asset.SHA512,
+		OS:          asset.OS,
+		Arch:        asset.Arch,
+	}, nil
+}
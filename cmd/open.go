@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 
+	"github.com/IgorBayerl/gdcli/internal/core"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +22,12 @@ func openCmd() *cobra.Command {
 }
 
 func runOpen(cobraCmd *cobra.Command, args []string) {
-	godotPath := filepath.Join("dependencies", "godot.exe")
+	godotPath, err := core.ProjectEnginePath()
+	if err != nil {
+		fmt.Printf("Godot executable not found: %v\n", err)
+		fmt.Println("Run 'gdcli install' to install the required version")
+		return
+	}
 
 	if _, err := os.Stat(godotPath); os.IsNotExist(err) {
 		fmt.Printf("Godot executable not found at %s\n", godotPath)
@@ -42,6 +47,13 @@ func runOpen(cobraCmd *cobra.Command, args []string) {
 		}
 	}
 
+	// The editor cannot load C# scripts of a project that was never built.
+	// Errors are fixed in the editor, so they do not stop it from opening.
+	if _, err := buildDotNetProject("Debug"); err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+		fmt.Println("💡 Check the C# build with 'gdcli build-dotnet'")
+	}
+
 	godotCmd := exec.Command(godotPath, "--path", ".", "--editor")
 	godotCmd.Stdout = nil
 	godotCmd.Stderr = nil
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/IgorBayerl/gdcli/internal/build"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(pruneCmd())
+}
+
+func pruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove engines not used by any project",
+		Long: `Remove every engine in the shared store that is not linked to a known project,
+along with interrupted downloads and installs, and builds of removed custom
+engines.
+Examples:
+  gdcli prune --dry-run    # Show what would be removed
+  gdcli prune`,
+		Run: runPrune,
+	}
+	cmd.Flags().Bool("dry-run", false, "Only show what would be removed")
+	return cmd
+}
+
+func runPrune(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	versions, err := core.InstalledEngines()
+	if err != nil {
+		fmt.Printf("❌ Error reading installed engines: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A dry run leaves the known projects as they are, stale ones hold on to
+	// no engine either way
+	if !dryRun {
+		stale, err := core.ForgetStaleProjects()
+		if err != nil {
+			fmt.Printf("❌ Error reading known projects: %v\n", err)
+			os.Exit(1)
+		}
+		for _, project := range stale {
+			fmt.Printf("Forgot project %s, it no longer exists or has no engine\n", project)
+		}
+	}
+
+	references, err := core.EngineReferences()
+	if err != nil {
+		fmt.Printf("❌ Error reading known projects: %v\n", err)
+		os.Exit(1)
+	}
+
+	var targets []string
+	var names []string
+	for _, v := range versions {
+		if len(references[core.EngineDir(v)]) == 0 {
+			targets = append(targets, core.EngineDir(v))
+			names = append(names, v.DisplayName)
+		}
+	}
+	if _, err := os.Stat(core.DownloadsDir()); err == nil {
+		targets = append(targets, core.DownloadsDir())
+		names = append(names, "interrupted downloads")
+	}
+	if _, err := os.Stat(core.StagingDir()); err == nil {
+		targets = append(targets, core.StagingDir())
+		names = append(names, "interrupted installs")
+	}
+
+	builds, err := build.UnusedBuilds()
+	if err != nil {
+		fmt.Printf("❌ Error reading engine builds: %v\n", err)
+		os.Exit(1)
+	}
+	for _, dir := range builds {
+		targets = append(targets, dir)
+		names = append(names, fmt.Sprintf("build %s of a removed engine", filepath.Base(dir)))
+	}
+
+	if len(targets) == 0 {
+		fmt.Println("Nothing to prune")
+		return
+	}
+
+	var total int64
+	failed := 0
+	for i, target := range targets {
+		size, _ := core.DirSize(target)
+
+		if dryRun {
+			fmt.Printf("Would remove %s (%s)\n", names[i], core.FormatBytes(size))
+			total += size
+			continue
+		}
+
+		if err := os.RemoveAll(target); err != nil {
+			fmt.Printf("❌ Error removing %s: %v\n", target, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Removed %s (%s)\n", names[i], core.FormatBytes(size))
+		total += size
+	}
+
+	if dryRun {
+		fmt.Printf("%s would be freed\n", core.FormatBytes(total))
+	} else {
+		fmt.Printf("✅ %s freed\n", core.FormatBytes(total))
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
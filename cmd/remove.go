@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/addons"
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(removeCmd())
+}
+
+func removeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an addon from the project",
+		Long: `Remove an addon from the dependencies or global addons in gdproj.json,
+delete addons/<name> and disable its editor plugin.
+Examples:
+  gdcli remove gut
+  gdcli remove gut --global    # Also delete it from ~/.gdcli/addons`,
+		Args: cobra.ExactArgs(1),
+		Run:  runRemove,
+	}
+	cmd.Flags().Bool("global", false, "Delete the addon from ~/.gdcli/addons")
+	return cmd
+}
+
+func runRemove(cmd *cobra.Command, args []string) {
+	name := args[0]
+	global, _ := cmd.Flags().GetBool("global")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		if global {
+			removeGlobal(name)
+			return
+		}
+		fmt.Println("❌ No gdproj.json found")
+		os.Exit(1)
+	}
+
+	if removeGlobalAddon(cfg, name) {
+		fmt.Printf("✅ Removed global addon %s from this project\n", name)
+		if global {
+			removeGlobal(name)
+		}
+		return
+	}
+	if global {
+		removeGlobal(name)
+		return
+	}
+
+	if _, ok := cfg.Dependencies[name]; !ok {
+		fmt.Printf("❌ %s is not a dependency of this project\n", name)
+		if len(cfg.Dependencies)+len(cfg.GlobalAddons) > 0 {
+			var names []string
+			for n := range cfg.Dependencies {
+				names = append(names, n)
+			}
+			names = append(names, cfg.GlobalAddons...)
+			sort.Strings(names)
+			fmt.Printf("💡 Dependencies: %s\n", strings.Join(names, ", "))
+		}
+		os.Exit(1)
+	}
+
+	if err := addons.Remove(name); err != nil {
+		fmt.Printf("❌ Failed to remove addon: %v\n", err)
+		os.Exit(1)
+	}
+
+	delete(cfg.Dependencies, name)
+	if err := config.SaveConfig(cfg); err != nil {
+		fmt.Printf("❌ Error saving gdproj.json: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := pinAddon(name, nil); err != nil {
+		fmt.Printf("⚠️  Failed to write %s: %v\n", config.LockFile, err)
+	}
+
+	fmt.Printf("✅ Removed %s\n", name)
+}
+
+// removeGlobalAddon stops the project from using the global addon, reporting
+// whether the project used it.
+func removeGlobalAddon(cfg *config.GodotConfig, name string) bool {
+	var kept []string
+	for _, n := range cfg.GlobalAddons {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	if len(kept) == len(cfg.GlobalAddons) {
+		return false
+	}
+
+	if err := addons.Remove(name); err != nil {
+		fmt.Printf("❌ Failed to remove addon: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg.GlobalAddons = kept
+	if err := config.SaveConfig(cfg); err != nil {
+		fmt.Printf("❌ Error saving gdproj.json: %v\n", err)
+		os.Exit(1)
+	}
+	return true
+}
+
+func removeGlobal(name string) {
+	if err := addons.RemoveGlobal(name); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Deleted global addon %s\n", name)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(runCmd())
+}
+
+func runCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run [script] [-- args...]",
+		Short: "Run a script from gdproj.json",
+		Long: `Run a script from the "scripts" section of gdproj.json. A "godot" command in
+the script runs the project's engine. Scripts named "pre<script>" and
+"post<script>" run before and after it. Without a script name, the available
+scripts are listed.
+Examples:
+  gdcli run                    # List scripts
+  gdcli run test               # Run the "test" script
+  gdcli run test -- --verbose  # Pass extra arguments to the script`,
+		Run: runRun,
+	}
+}
+
+func runRun(cmd *cobra.Command, args []string) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println("❌ No config found")
+		fmt.Println("💡 First create a project with: gdcli init")
+		return
+	}
+
+	if len(args) == 0 {
+		listScripts(cfg)
+		return
+	}
+
+	name := args[0]
+	script, ok := cfg.Scripts[name]
+	if !ok {
+		fmt.Printf("❌ Script '%s' not found in gdproj.json\n", name)
+		listScripts(cfg)
+		os.Exit(1)
+	}
+
+	steps := []string{"pre" + name, name, "post" + name}
+	for _, step := range steps {
+		command, ok := cfg.Scripts[step]
+		if !ok {
+			continue
+		}
+		if step == name {
+			command = script + quoteArgs(args[1:])
+		}
+
+		fmt.Printf("> %s: %s\n", step, command)
+		if code := runScript(command); code != 0 {
+			fmt.Printf("❌ Script '%s' failed with exit code %d\n", step, code)
+			os.Exit(code)
+		}
+	}
+}
+
+func listScripts(cfg *config.GodotConfig) {
+	if len(cfg.Scripts) == 0 {
+		fmt.Println("No scripts defined in gdproj.json")
+		return
+	}
+
+	var names []string
+	for name := range cfg.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("Available scripts:")
+	for _, name := range names {
+		fmt.Printf("  %s\n    %s\n", name, cfg.Scripts[name])
+	}
+}
+
+// godotCommand matches "godot" where it is run as a command: at the start of
+// the script or after a shell operator.
+var godotCommand = regexp.MustCompile(`(^|[;&|(]\s*|^\s+)godot(\s|$)`)
+
+// runScript runs the command with the system shell and returns its exit code.
+func runScript(command string) int {
+	env := os.Environ()
+
+	if enginePath, err := core.ProjectConsolePath(); err == nil {
+		if absPath, err := filepath.Abs(enginePath); err == nil {
+			enginePath = absPath
+		}
+
+		// The path is inserted literally, "$" in it is no group reference
+		quoted := quoteArg(enginePath)
+		command = godotCommand.ReplaceAllStringFunc(command, func(match string) string {
+			groups := godotCommand.FindStringSubmatch(match)
+			return groups[1] + quoted + groups[2]
+		})
+		env = append(env, "GODOT="+enginePath)
+
+		// Commands run by the script, e.g. other tools or nested scripts, find
+		// the engine as "godot" on PATH
+		shimDir, err := godotShim(enginePath)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to put godot on PATH: %v\n", err)
+		} else {
+			defer os.RemoveAll(shimDir)
+			env = append(env, "PATH="+shimDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		}
+	} else if godotCommand.MatchString(command) {
+		fmt.Printf("⚠️  %v, run 'gdcli install' to use godot in scripts\n", err)
+	}
+
+	var shell *exec.Cmd
+	if runtime.GOOS == "windows" {
+		shell = exec.Command("cmd", "/C", command)
+	} else {
+		shell = exec.Command("sh", "-c", command)
+	}
+	shell.Env = env
+	shell.Stdin = os.Stdin
+	shell.Stdout = os.Stdout
+	shell.Stderr = os.Stderr
+
+	if err := shell.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		fmt.Printf("❌ Error running script: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// godotShim creates a temporary directory with a "godot" command running the
+// engine, a symlink or a godot.cmd wrapper on Windows. The caller removes the
+// directory.
+func godotShim(enginePath string) (string, error) {
+	dir, err := os.MkdirTemp("", "gdcli-run-")
+	if err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS == "windows" {
+		err = os.WriteFile(filepath.Join(dir, "godot.cmd"), []byte("@\""+enginePath+"\" %*\r\n"), 0644)
+	} else {
+		err = os.Symlink(enginePath, filepath.Join(dir, "godot"))
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// quoteArgs quotes extra arguments for appending to a script.
+func quoteArgs(args []string) string {
+	var quoted string
+	for _, arg := range args {
+		quoted += " " + quoteArg(arg)
+	}
+	return quoted
+}
+
+func quoteArg(arg string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/IgorBayerl/gdcli/internal/addons"
+	"github.com/IgorBayerl/gdcli/internal/assetlib"
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(searchCmd())
+}
+
+func searchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search the Godot Asset Library for addons",
+		Long: `Search the Godot Asset Library for addons compatible with the project's
+engine version.
+Examples:
+  gdcli search dialogue
+  gdcli search terrain --category "3D Tools"
+  gdcli search shader --page 2            # Next page of results
+  gdcli search gut --godot-version 4.2    # Search for another engine version
+  gdcli search gut --godot-version any    # Search every engine version`,
+		Args: cobra.ExactArgs(1),
+		Run:  runSearch,
+	}
+	cmd.Flags().String("category", "", "Only show addons of the category, by name or ID")
+	cmd.Flags().String("godot-version", "", "Engine version to search addons for, defaults to the project's")
+	cmd.Flags().Int("limit", 20, "Maximum number of results")
+	cmd.Flags().Int("page", 1, "Page of results to show")
+	return cmd
+}
+
+func runSearch(cmd *cobra.Command, args []string) {
+	category, _ := cmd.Flags().GetString("category")
+	godotVersion, _ := cmd.Flags().GetString("godot-version")
+	limit, _ := cmd.Flags().GetInt("limit")
+	page, _ := cmd.Flags().GetInt("page")
+
+	if limit < 1 || page < 1 {
+		fmt.Println("❌ --limit and --page must be at least 1")
+		os.Exit(1)
+	}
+
+	if godotVersion == "" {
+		godotVersion = projectGodotVersion()
+	} else if godotVersion == "any" {
+		godotVersion = ""
+	}
+
+	if category != "" {
+		id, err := categoryID(category)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		category = id
+	}
+
+	assets, total, err := assetlib.Search(assetlib.SearchOptions{
+		Query:        args[0],
+		GodotVersion: godotVersion,
+		Category:     category,
+		MaxResults:   limit,
+		Page:         page - 1,
+	})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	forVersion := ""
+	if godotVersion != "" {
+		forVersion = " for Godot " + godotVersion
+	}
+	if len(assets) == 0 {
+		if page > 1 && total > 0 {
+			fmt.Printf("No more addons, %d found matching '%s'%s\n", total, args[0], forVersion)
+			return
+		}
+		fmt.Printf("No addons found matching '%s'%s\n", args[0], forVersion)
+		return
+	}
+
+	fmt.Printf("Found %d addons matching '%s'%s", total, args[0], forVersion)
+	if total > len(assets) {
+		first := (page - 1) * limit
+		fmt.Printf(", showing %d-%d", first+1, first+len(assets))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tVERSION\tGODOT\tLICENSE\tAUTHOR")
+	for _, a := range assets {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, a.Title, a.VersionString, a.GodotVersion, a.License, a.Author)
+	}
+	w.Flush()
+
+	fmt.Printf("\n💡 Add one with: gdcli add %s%s\n", addons.AssetPrefix, assets[0].ID)
+	if page*limit < total {
+		fmt.Printf("   More results with: --page %d\n", page+1)
+	}
+}
+
+// projectGodotVersion returns the "major.minor" engine version of the project
+// in the current directory, or "" outside a project. The linked engine is
+// used if there is one, otherwise the newest version matching gdproj.json.
+func projectGodotVersion() string {
+	var version string
+	if engine, err := core.LoadProjectEngine(); err == nil {
+		version = engine.Version.Version
+	} else if cfg, err := config.LoadConfig(); err == nil {
+		core.RefreshManifest()
+		if v, err := core.ResolveVersion(cfg.EngineVersion, cfg.IsDotNet); err == nil {
+			version = v.Version
+		}
+	}
+
+	v, err := semver.Parse(version)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// categoryID returns the ID of the category given by name or ID.
+func categoryID(category string) (string, error) {
+	if _, err := strconv.Atoi(category); err == nil {
+		return category, nil
+	}
+
+	categories, err := assetlib.Categories()
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, category) {
+			return c.ID, nil
+		}
+		names = append(names, c.Name)
+	}
+	return "", fmt.Errorf("unknown category '%s', available categories: %s", category, strings.Join(names, ", "))
+}
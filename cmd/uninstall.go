@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd())
+}
+
+func uninstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uninstall <version>",
+		Short: "Remove a Godot version from the engine store",
+		Long: `Remove an installed Godot version from the shared engine store.
+Examples:
+  gdcli uninstall "4.3.0 (Mono)"
+  gdcli uninstall 4.3-stable_standard_amd64
+  gdcli uninstall 4.3.0 --force    # Remove even if projects still use it`,
+		Args: cobra.ExactArgs(1),
+		Run:  runUninstall,
+	}
+	cmd.Flags().Bool("force", false, "Remove the version even if projects still use it")
+	return cmd
+}
+
+func runUninstall(cmd *cobra.Command, args []string) {
+	force, _ := cmd.Flags().GetBool("force")
+
+	version, err := core.FindInstalledEngine(args[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		fmt.Println("💡 See installed versions with: gdcli list")
+		os.Exit(1)
+	}
+
+	engineDir := core.EngineDir(version)
+
+	references, err := core.EngineReferences()
+	if err != nil {
+		fmt.Printf("❌ Error reading known projects: %v\n", err)
+		os.Exit(1)
+	}
+	if projects := references[engineDir]; len(projects) > 0 && !force {
+		fmt.Printf("❌ %s is used by:\n", version.DisplayName)
+		for _, p := range projects {
+			fmt.Printf("  - %s\n", p)
+		}
+		fmt.Println("💡 Use --force to remove it anyway")
+		os.Exit(1)
+	}
+
+	size, _ := core.DirSize(engineDir)
+	if err := os.RemoveAll(engineDir); err != nil {
+		fmt.Printf("❌ Error removing %s: %v\n", engineDir, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Uninstalled %s (%s freed)\n", version.DisplayName, core.FormatBytes(size))
+}
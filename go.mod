@@ -2,7 +2,11 @@ module github.com/IgorBayerl/gdcli
 
 go 1.23.5
 
-require github.com/AlecAivazis/survey/v2 v2.3.7
+require (
+	github.com/AlecAivazis/survey/v2 v2.3.7
+	github.com/spf13/cobra v1.8.1
+	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
+)
 
 require (
 	github.com/fsnotify/fsnotify v1.7.0 // indirect
@@ -20,7 +24,6 @@ require (
 	github.com/sourcegraph/conc v0.3.0 // indirect
 	github.com/spf13/afero v1.11.0 // indirect
 	github.com/spf13/cast v1.6.0 // indirect
-	github.com/spf13/cobra v1.8.1 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/spf13/viper v1.19.0 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
@@ -28,7 +31,6 @@ require (
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9 // indirect
 	golang.org/x/sys v0.18.0 // indirect
-	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
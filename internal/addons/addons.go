@@ -0,0 +1,314 @@
+// Package addons installs the addons listed in gdproj.json into the project's
+// addons directory.
+package addons
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/assetlib"
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/godot"
+	"github.com/IgorBayerl/gdcli/internal/source"
+)
+
+const (
+	// Dir is the project directory Godot expects addons in.
+	Dir = "addons"
+
+	// InstalledFile records which build of each addon is installed, in the
+	// project's dependencies directory.
+	InstalledFile = "addons.json"
+
+	// AssetPrefix marks an Asset Library ID as source, e.g. "asset:67".
+	AssetPrefix = "asset:"
+)
+
+// Install fetches the addon from src and installs it into addons/<name>,
+// enabling its editor plugin. When locked is set, the pinned build is
+// installed instead of the newest one. An empty name is taken from the
+// addon's directory in the source. The installed name and its lock entry are
+// returned.
+func Install(name, src string, locked *config.AddonLock) (string, config.AddonLock, error) {
+	name, lock, err := install(Dir, name, src, locked)
+	if err != nil {
+		return "", config.AddonLock{}, err
+	}
+
+	if err := enablePlugin(name); err != nil {
+		fmt.Printf("Warning: failed to enable the %s plugin: %v\n", name, err)
+	}
+
+	if err := recordInstalled(name, &lock); err != nil {
+		return "", config.AddonLock{}, err
+	}
+	return name, lock, nil
+}
+
+// install fetches the addon from src into dir/<name>.
+func install(dir, name, src string, locked *config.AddonLock) (string, config.AddonLock, error) {
+	if err := os.MkdirAll(core.DownloadsDir(), 0755); err != nil {
+		return "", config.AddonLock{}, err
+	}
+	tempDir, err := os.MkdirTemp(core.DownloadsDir(), "addon-")
+	if err != nil {
+		return "", config.AddonLock{}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	root, lock, err := fetch(src, filepath.Join(tempDir, "source"), locked)
+	if err != nil {
+		return "", config.AddonLock{}, err
+	}
+
+	addonDir, err := findAddon(root, name)
+	if err != nil {
+		return "", config.AddonLock{}, fmt.Errorf("%s: %v", src, err)
+	}
+	if name == "" {
+		name = filepath.Base(addonDir)
+		if addonDir == root {
+			name = defaultName(src)
+		}
+	}
+	if err := ValidateName(name); err != nil {
+		return "", config.AddonLock{}, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", config.AddonLock{}, err
+	}
+	if err := replaceDir(addonDir, filepath.Join(dir, name)); err != nil {
+		return "", config.AddonLock{}, err
+	}
+	return name, lock, nil
+}
+
+// replaceDir replaces target with a copy of src. The copy is made next to
+// target and swapped in once complete. Godot ignores hidden directories, so
+// the editor never sees a partial copy.
+func replaceDir(src, target string) error {
+	staging := filepath.Join(filepath.Dir(target), "."+filepath.Base(target)+".tmp")
+	defer os.RemoveAll(staging)
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	if err := core.CopyDir(src, staging); err != nil {
+		return fmt.Errorf("failed to copy addon: %v", err)
+	}
+	// Addons taken from the root of a git checkout bring its history along
+	if err := os.RemoveAll(filepath.Join(staging, ".git")); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	return os.Rename(staging, target)
+}
+
+// Remove deletes addons/<name> and disables its editor plugin.
+func Remove(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	if err := godot.DisablePlugin(pluginPath(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to disable the %s plugin: %v", name, err)
+	}
+	if err := os.RemoveAll(filepath.Join(Dir, name)); err != nil {
+		return err
+	}
+	return recordInstalled(name, nil)
+}
+
+// IsInstalled reports whether the build in lock is the one installed in
+// addons/<name>.
+func IsInstalled(name string, lock config.AddonLock) bool {
+	if _, err := os.Stat(filepath.Join(Dir, name)); err != nil {
+		return false
+	}
+	installed, ok := loadInstalled()[name]
+	return ok && installed == lock
+}
+
+// ValidateName rejects addon names that are not a single directory name.
+func ValidateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid addon name '%s'", name)
+	}
+	return nil
+}
+
+// fetch downloads the source into dest and returns the directory holding its
+// files, which for local sources is the source itself.
+func fetch(src, dest string, locked *config.AddonLock) (string, config.AddonLock, error) {
+	lock := config.AddonLock{Source: src}
+
+	switch {
+	case strings.HasPrefix(src, AssetPrefix):
+		id := strings.TrimPrefix(src, AssetPrefix)
+		if locked != nil && locked.URL != "" {
+			lock = *locked
+		} else {
+			asset, err := assetlib.GetAsset(id)
+			if err != nil {
+				return "", lock, err
+			}
+			fmt.Printf("Found %s %s by %s\n", asset.Title, asset.VersionString, asset.Author)
+			lock.Version = asset.VersionString
+			lock.URL = asset.DownloadURL
+		}
+
+		checksum, err := source.Archive(lock.URL, dest, lock.SHA512)
+		if err != nil {
+			return "", lock, err
+		}
+		lock.SHA512 = checksum
+		return dest, lock, nil
+
+	case source.IsGitURL(src):
+		ref := src
+		if locked != nil && locked.Version != "" {
+			repo, _, _ := source.SplitRef(src)
+			ref = repo + "#" + locked.Version
+		}
+
+		commit, err := source.Git(ref, dest)
+		if err != nil {
+			return "", lock, err
+		}
+		lock.Version = commit
+		return dest, lock, nil
+
+	case source.IsArchiveURL(src):
+		expected := ""
+		if locked != nil {
+			expected = locked.SHA512
+		}
+
+		checksum, err := source.Archive(src, dest, expected)
+		if err != nil {
+			return "", lock, err
+		}
+		lock.SHA512 = checksum
+		return dest, lock, nil
+	}
+
+	if info, err := os.Stat(src); err == nil && info.IsDir() {
+		return src, lock, nil
+	}
+	return "", lock, fmt.Errorf("unsupported addon source '%s', use %s<id>, a git URL, an archive URL or a local directory", src, AssetPrefix)
+}
+
+// findAddon returns the addon directory in a fetched source. Sources usually
+// hold the addon in addons/<name>, possibly wrapped in a top-level folder as
+// in archives of a repository. A source with a plugin.cfg at its root is the
+// addon itself.
+func findAddon(root, name string) (string, error) {
+	queue := []string{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if entries, err := os.ReadDir(filepath.Join(current, Dir)); err == nil {
+			var candidates []string
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				if entry.Name() == name {
+					return filepath.Join(current, Dir, name), nil
+				}
+				candidates = append(candidates, entry.Name())
+			}
+
+			if len(candidates) == 1 {
+				return filepath.Join(current, Dir, candidates[0]), nil
+			}
+			if len(candidates) > 1 {
+				return "", fmt.Errorf("contains several addons (%s), choose one with --name", strings.Join(candidates, ", "))
+			}
+		}
+
+		if _, err := os.Stat(filepath.Join(current, "plugin.cfg")); err == nil {
+			return current, nil
+		}
+
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && entry.Name() != ".git" {
+				queue = append(queue, filepath.Join(current, entry.Name()))
+			}
+		}
+	}
+	return "", fmt.Errorf("no %s directory or plugin.cfg found", Dir)
+}
+
+// defaultName names an addon that is the root of its source after the
+// source, e.g. "gut" for "https://github.com/bitwes/gut.git#v9.3.0".
+func defaultName(src string) string {
+	if source.IsGitURL(src) {
+		repo, _, _ := source.SplitRef(src)
+		src = strings.TrimSuffix(repo, ".git")
+	} else if source.IsArchiveURL(src) {
+		src = strings.SplitN(src, "?", 2)[0]
+		for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+			src = strings.TrimSuffix(src, ext)
+		}
+	}
+
+	src = strings.TrimRight(filepath.ToSlash(src), "/")
+	return path.Base(strings.TrimPrefix(src, AssetPrefix))
+}
+
+func pluginPath(name string) string {
+	return "res://" + path.Join(Dir, name, "plugin.cfg")
+}
+
+// enablePlugin enables the addon's editor plugin, if it has one.
+func enablePlugin(name string) error {
+	if _, err := os.Stat(filepath.Join(Dir, name, "plugin.cfg")); err != nil {
+		return nil
+	}
+	return godot.EnablePlugin(pluginPath(name))
+}
+
+func installedPath() string {
+	return filepath.Join(core.DependenciesDir, InstalledFile)
+}
+
+func loadInstalled() map[string]config.AddonLock {
+	installed := make(map[string]config.AddonLock)
+	if data, err := os.ReadFile(installedPath()); err == nil {
+		json.Unmarshal(data, &installed)
+	}
+	return installed
+}
+
+// recordInstalled records the build installed as name, or its removal when
+// lock is nil.
+func recordInstalled(name string, lock *config.AddonLock) error {
+	installed := loadInstalled()
+	if lock != nil {
+		installed[name] = *lock
+	} else {
+		delete(installed, name)
+	}
+
+	if err := os.MkdirAll(core.DependenciesDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(installed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(installedPath(), data, 0644)
+}
@@ -0,0 +1,89 @@
+package addons
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/IgorBayerl/gdcli/internal/config"
+	"github.com/IgorBayerl/gdcli/internal/core"
+)
+
+// globalSource is the source recorded for global addons linked into a
+// project.
+const globalSource = "global"
+
+// GlobalDir holds the addons installed with 'gdcli add --global', shared by
+// every project that opts in to them.
+func GlobalDir() string {
+	return filepath.Join(core.GetHomePath(), "addons")
+}
+
+// InstallGlobal fetches the addon from src into the global addons directory.
+func InstallGlobal(name, src string) (string, config.AddonLock, error) {
+	return install(GlobalDir(), name, src, nil)
+}
+
+// IsGlobalInstalled reports whether the addon is in the global addons
+// directory.
+func IsGlobalInstalled(name string) bool {
+	info, err := os.Stat(filepath.Join(GlobalDir(), name))
+	return err == nil && info.IsDir()
+}
+
+// RemoveGlobal deletes the addon from the global addons directory. Projects
+// linking it keep a dangling link until they stop using it.
+func RemoveGlobal(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if !IsGlobalInstalled(name) {
+		return fmt.Errorf("global addon %s is not installed", name)
+	}
+	return os.RemoveAll(filepath.Join(GlobalDir(), name))
+}
+
+// LinkGlobal makes the global addon available as addons/<name> and enables
+// its editor plugin. The addon is linked where possible so updates to the
+// global addon apply to every project, and copied again on every call
+// otherwise.
+func LinkGlobal(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if !IsGlobalInstalled(name) {
+		return fmt.Errorf("global addon %s is not installed", name)
+	}
+
+	globalPath := filepath.Join(GlobalDir(), name)
+	target := filepath.Join(Dir, name)
+
+	if info, err := os.Lstat(target); err == nil {
+		if loadInstalled()[name].Source != globalSource {
+			return fmt.Errorf("%s already exists and is not a global addon", filepath.ToSlash(target))
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if dest, err := os.Readlink(target); err == nil && dest == globalPath {
+				return enablePlugin(name)
+			}
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(Dir, 0755); err != nil {
+		return err
+	}
+	if err := os.Symlink(globalPath, target); err != nil {
+		// Creating symbolic links needs extra privileges on Windows
+		if err := replaceDir(globalPath, target); err != nil {
+			return err
+		}
+	}
+
+	if err := enablePlugin(name); err != nil {
+		fmt.Printf("Warning: failed to enable the %s plugin: %v\n", name, err)
+	}
+	return recordInstalled(name, &config.AddonLock{Source: globalSource})
+}
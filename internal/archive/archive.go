@@ -0,0 +1,314 @@
+// Package archive extracts zip and tar archives. Entries escaping the
+// destination directory, directly or through symlinks, are rejected.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxLinkDepth limits how many symlinks are followed to resolve a link.
+const maxLinkDepth = 40
+
+// Extract extracts a .zip, .tar, .tar.gz or .tgz archive into dest, picking
+// the format from the file name.
+func Extract(src, dest string) error {
+	name := strings.ToLower(src)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return ExtractZip(src, dest)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ExtractTar(src, dest, true)
+	case strings.HasSuffix(name, ".tar"):
+		return ExtractTar(src, dest, false)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", filepath.Base(src))
+	}
+}
+
+// link is a symlink entry of an archive. Symlinks are created after every
+// other entry, so no file is written through them.
+type link struct {
+	name   string // Name of the entry in the archive
+	path   string
+	target string
+}
+
+// safePath joins name onto dest and rejects names escaping dest.
+func safePath(dest, name string) (string, error) {
+	path := filepath.Join(dest, name)
+	rel, err := filepath.Rel(dest, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) ||
+		filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return path, nil
+}
+
+// entryPath returns where the entry is extracted to, rejecting entries whose
+// parent directories in dest are symlinks.
+func entryPath(dest, name string) (string, error) {
+	path, err := safePath(dest, name)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(dest, filepath.Dir(path))
+	if err != nil || rel == "." {
+		return path, err
+	}
+
+	current := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("illegal path in archive: %s is inside a symlink", name)
+		}
+	}
+	return path, nil
+}
+
+func ExtractZip(src, dest string) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var links []link
+	for _, f := range r.File {
+		path, err := entryPath(dest, f.Name)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case f.FileInfo().IsDir():
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case f.Mode()&os.ModeSymlink != 0:
+			// The target of a symlink is stored as the content of the entry
+			target, err := readZipFile(f)
+			if err != nil {
+				return err
+			}
+			links = append(links, link{name: f.Name, path: path, target: string(target)})
+		case f.Mode().IsRegular():
+			if err := extractZipFile(f, path); err != nil {
+				return err
+			}
+		}
+	}
+	return createLinks(dest, links)
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func extractZipFile(f *zip.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return writeFile(path, rc, f.Mode().Perm())
+}
+
+func ExtractTar(src, dest string, gzipped bool) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if gzipped {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var links []link
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return createLinks(dest, links)
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := entryPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(path, tr, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			links = append(links, link{name: header.Name, path: path, target: header.Linkname})
+		}
+	}
+}
+
+// writeFile writes a file with the permissions from the archive, keeping
+// executable bits. An existing symlink at path is replaced, not written
+// through.
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if mode == 0 {
+		mode = 0644
+	}
+
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// createLinks creates the symlinks of an archive once everything else is
+// extracted, and checks that each of them resolves to a location inside
+// dest, also through other links.
+func createLinks(dest string, links []link) error {
+	for _, l := range links {
+		l.target = filepath.FromSlash(l.target)
+		if filepath.IsAbs(l.target) || filepath.VolumeName(l.target) != "" {
+			return fmt.Errorf("illegal symlink in archive: %s -> %s", l.name, l.target)
+		}
+		if _, err := safePath(dest, filepath.Join(filepath.Dir(l.name), l.target)); err != nil {
+			return fmt.Errorf("illegal symlink in archive: %s -> %s", l.name, l.target)
+		}
+
+		// Links may be nested in directories created by earlier links
+		if _, err := entryPath(dest, l.name); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(l.path); err != nil {
+			return err
+		}
+		if err := os.Symlink(l.target, l.path); err != nil {
+			return err
+		}
+	}
+
+	// Checked once all links exist, a later link may change where an earlier
+	// one points to
+	for _, l := range links {
+		if err := resolveInside(dest, l.path); err != nil {
+			os.Remove(l.path)
+			return fmt.Errorf("illegal symlink in archive: %s -> %s: %v", l.name, l.target, err)
+		}
+	}
+	return nil
+}
+
+// resolveInside follows the symlink at path one component at a time and
+// fails if the resolution leaves root. Missing components are resolved
+// lexically.
+func resolveInside(root, path string) error {
+	current := filepath.Dir(path)
+	pending := []string{filepath.Base(path)}
+	followed := 0
+
+	for len(pending) > 0 {
+		part := pending[0]
+		pending = pending[1:]
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, part)
+		}
+
+		rel, err := filepath.Rel(root, current)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("resolves outside of the archive")
+		}
+
+		info, err := os.Lstat(current)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		followed++
+		if followed > maxLinkDepth {
+			return fmt.Errorf("too many levels of symlinks")
+		}
+		target, err := os.Readlink(current)
+		if err != nil {
+			return err
+		}
+		if filepath.IsAbs(target) {
+			return fmt.Errorf("resolves outside of the archive")
+		}
+		current = filepath.Dir(current)
+		pending = append(strings.Split(target, string(filepath.Separator)), pending...)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// entry is a file, directory or symlink of a test archive.
+type entry struct {
+	name string
+	mode os.FileMode
+	body string // Content of files, target of symlinks
+}
+
+func file(name string, mode os.FileMode) entry {
+	return entry{name: name, mode: mode, body: "content of " + name}
+}
+
+func dir(name string) entry {
+	return entry{name: name, mode: os.ModeDir | 0755}
+}
+
+func symlink(name, target string) entry {
+	return entry{name: name, mode: os.ModeSymlink | 0777, body: target}
+}
+
+// formats are the archive names tested, one per supported format.
+var formats = []string{"test.zip", "test.tar", "test.tar.gz"}
+
+// writeArchive writes the entries into an archive of the format given by
+// name and returns its path.
+func writeArchive(t *testing.T, name string, entries []entry) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(name, ".zip") {
+		writeZip(t, f, entries)
+	} else if strings.HasSuffix(name, ".gz") {
+		gz := gzip.NewWriter(f)
+		writeTar(t, gz, entries)
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		writeTar(t, f, entries)
+	}
+	return path
+}
+
+func writeZip(t *testing.T, out io.Writer, entries []entry) {
+	t.Helper()
+	w := zip.NewWriter(out)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.mode.IsDir() && !strings.HasSuffix(header.Name, "/") {
+			header.Name += "/"
+		}
+		header.SetMode(e.mode)
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTar(t *testing.T, out io.Writer, entries []entry) {
+	t.Helper()
+	w := tar.NewWriter(out)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: int64(e.mode.Perm())}
+		switch {
+		case e.mode.IsDir():
+			header.Typeflag = tar.TypeDir
+		case e.mode&os.ModeSymlink != 0:
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.body
+		default:
+			header.Typeflag = tar.TypeReg
+			header.Size = int64(len(e.body))
+		}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := w.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// needSymlinks skips the test where creating symlinks needs privileges.
+func needSymlinks(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on Windows")
+	}
+}
+
+// extractInto extracts the entries into a dest directory next to an outside
+// directory and returns both.
+func extractInto(t *testing.T, format string, entries []entry) (dest, outside string, err error) {
+	t.Helper()
+	src := writeArchive(t, format, entries)
+	root := t.TempDir()
+	dest = filepath.Join(root, "dest")
+	outside = filepath.Join(root, "outside")
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dest, outside, Extract(src, dest)
+}
+
+func TestExtract(t *testing.T) {
+	needSymlinks(t)
+	entries := []entry{
+		dir("godot/"),
+		file("godot/bin/godot", 0755),
+		file("godot/lib/libfoo.so.1", 0644),
+		symlink("godot/lib/libfoo.so", "libfoo.so.1"),
+		symlink("godot/current", "lib"),
+		file("godot/README.md", 0644),
+	}
+
+	for _, format := range formats {
+		dest, _, err := extractInto(t, format, entries)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dest, "godot", "current", "libfoo.so"))
+		if err != nil || string(data) != "content of godot/lib/libfoo.so.1" {
+			t.Errorf("%s: reading through links: %q, %v", format, data, err)
+		}
+		if target, err := os.Readlink(filepath.Join(dest, "godot", "lib", "libfoo.so")); err != nil || target != "libfoo.so.1" {
+			t.Errorf("%s: link target %q, %v", format, target, err)
+		}
+	}
+}
+
+func TestExtractKeepsExecutableBit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no executable bit")
+	}
+	entries := []entry{file("bin/godot", 0755), file("README.md", 0644), file("no-mode", 0)}
+
+	for _, format := range formats {
+		dest, _, err := extractInto(t, format, entries)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		modes := map[string]os.FileMode{"bin/godot": 0755, "README.md": 0644, "no-mode": 0644}
+		for name, want := range modes {
+			info, err := os.Stat(filepath.Join(dest, name))
+			if err != nil {
+				t.Fatalf("%s: %v", format, err)
+			}
+			if got := info.Mode().Perm(); got != want {
+				t.Errorf("%s: %s has mode %v, want %v", format, name, got, want)
+			}
+		}
+	}
+}
+
+func TestExtractRejectsEscapes(t *testing.T) {
+	needSymlinks(t)
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{"parent entry", []entry{file("../outside/evil", 0644)}},
+		{"nested parent entry", []entry{dir("godot/"), file("godot/../../outside/evil", 0644)}},
+		{"absolute entry", []entry{file("/outside/evil", 0644)}},
+		{"link outside", []entry{symlink("evil", "../outside")}},
+		{"absolute link", []entry{symlink("evil", "/etc")}},
+		{"link inside link", []entry{dir("lib/"), symlink("current", "lib"), symlink("current/evil", "../lib")}},
+		// Each link stays inside on its own, together they lead outside
+		{"chained links", []entry{symlink("evil", "a/up/.."), symlink("a/up", "..")}},
+	}
+
+	for _, format := range formats {
+		for _, tt := range tests {
+			dest, outside, err := extractInto(t, format, tt.entries)
+			if err == nil {
+				t.Errorf("%s: %s was extracted", format, tt.name)
+			}
+			if _, err := os.Lstat(filepath.Join(outside, "evil")); err == nil {
+				t.Errorf("%s: %s wrote outside of dest", format, tt.name)
+			}
+			if _, err := os.Stat(filepath.Join(dest, "evil")); err == nil {
+				t.Errorf("%s: %s left a usable link", format, tt.name)
+			}
+		}
+	}
+}
+
+func TestExtractThroughExistingLinks(t *testing.T) {
+	needSymlinks(t)
+
+	for _, format := range formats {
+		src := writeArchive(t, format, []entry{file("lib/evil", 0644), file("config", 0644)})
+		root := t.TempDir()
+		dest := filepath.Join(root, "dest")
+		outside := filepath.Join(root, "outside")
+		for _, d := range []string{dest, outside} {
+			if err := os.MkdirAll(d, 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(outside, "config"), []byte("keep"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// A file is not written through a symlinked parent directory
+		if err := os.Symlink(outside, filepath.Join(dest, "lib")); err != nil {
+			t.Fatal(err)
+		}
+		if err := Extract(src, dest); err == nil {
+			t.Errorf("%s: file written through a symlinked directory", format)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "evil")); err == nil {
+			t.Errorf("%s: file created outside of dest", format)
+		}
+
+		// A symlink in place of a file is replaced, not written through
+		os.Remove(filepath.Join(dest, "lib"))
+		if err := os.Symlink(filepath.Join(outside, "config"), filepath.Join(dest, "config")); err != nil {
+			t.Fatal(err)
+		}
+		if err := Extract(src, dest); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(outside, "config")); string(data) != "keep" {
+			t.Errorf("%s: file written through a symlink: %q", format, data)
+		}
+		if info, err := os.Lstat(filepath.Join(dest, "config")); err != nil || !info.Mode().IsRegular() {
+			t.Errorf("%s: symlink not replaced by the file", format)
+		}
+	}
+}
+
+func TestExtractUnsupportedFormat(t *testing.T) {
+	if err := Extract("engine.rar", t.TempDir()); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("Extract(engine.rar) = %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Package assetlib is a client for the Godot Asset Library REST API.
+package assetlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the official Asset Library API.
+const DefaultAPIURL = "https://godotengine.org/asset-library/api"
+
+// APIURL is the base URL of the Asset Library API. Set
+// GDCLI_ASSET_LIBRARY_URL to use a mirror or a self-hosted instance.
+var APIURL = apiURL()
+
+func apiURL() string {
+	if u := os.Getenv("GDCLI_ASSET_LIBRARY_URL"); u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	return DefaultAPIURL
+}
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Asset is an entry of the Asset Library.
+type Asset struct {
+	ID             string `json:"asset_id"`
+	Title          string `json:"title"`
+	Author         string `json:"author"`
+	Category       string `json:"category"`
+	SupportLevel   string `json:"support_level"`  // "official", "community" or "testing"
+	GodotVersion   string `json:"godot_version"`  // Oldest engine version supported, e.g. "4.2"
+	VersionString  string `json:"version_string"` // Version of the asset, e.g. "1.2.0"
+	License        string `json:"cost"`           // The API calls the license "cost"
+	DownloadURL    string `json:"download_url"`
+	DownloadCommit string `json:"download_commit"`
+	BrowseURL      string `json:"browse_url"`
+}
+
+// Category groups assets, e.g. "2D Tools".
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SearchOptions filters Search results. Empty fields are not filtered on.
+type SearchOptions struct {
+	Query        string
+	GodotVersion string // Engine version as "major.minor", e.g. "4.3"
+	Category     string // Category ID
+	MaxResults   int
+	Page         int // Page of MaxResults results, starting at 0
+}
+
+type searchResponse struct {
+	Result     []Asset `json:"result"`
+	TotalItems int     `json:"total_items"`
+}
+
+// Search returns the addons matching the options, most recently updated
+// first, and the total number of matches.
+func Search(opts SearchOptions) ([]Asset, int, error) {
+	query := url.Values{}
+	query.Set("type", "addon")
+	query.Set("sort", "updated")
+	if opts.Query != "" {
+		query.Set("filter", opts.Query)
+	}
+	if opts.GodotVersion != "" {
+		query.Set("godot_version", opts.GodotVersion)
+	}
+	if opts.Category != "" {
+		query.Set("category", opts.Category)
+	}
+	if opts.MaxResults > 0 {
+		query.Set("max_results", strconv.Itoa(opts.MaxResults))
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+
+	var resp searchResponse
+	if err := get("/asset?"+query.Encode(), &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to search the Asset Library: %v", err)
+	}
+	return resp.Result, resp.TotalItems, nil
+}
+
+// Categories returns the categories of addons.
+func Categories() ([]Category, error) {
+	var resp struct {
+		Categories []Category `json:"categories"`
+	}
+	if err := get("/configure?type=addon", &resp); err != nil {
+		return nil, fmt.Errorf("failed to get Asset Library categories: %v", err)
+	}
+	return resp.Categories, nil
+}
+
+// GetAsset returns the asset with the given ID.
+func GetAsset(id string) (*Asset, error) {
+	var asset Asset
+	if err := get("/asset/"+url.PathEscape(id), &asset); err != nil {
+		return nil, fmt.Errorf("failed to get asset %s: %v", id, err)
+	}
+	if asset.DownloadURL == "" {
+		return nil, fmt.Errorf("asset %s has no download", id)
+	}
+	return &asset, nil
+}
+
+func get(path string, v interface{}) error {
+	resp, err := client.Get(APIURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,212 @@
+package assetlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeLibrary serves an Asset Library API with the given addons and points
+// APIURL at it. It records the query of the last search.
+func fakeLibrary(t *testing.T, assets []Asset) *string {
+	t.Helper()
+	var lastQuery string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/asset", func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = r.URL.RawQuery
+		q := r.URL.Query()
+
+		var matches []Asset
+		for _, a := range assets {
+			if strings.Contains(strings.ToLower(a.Title), strings.ToLower(q.Get("filter"))) &&
+				(q.Get("godot_version") == "" || a.GodotVersion == q.Get("godot_version")) &&
+				(q.Get("category") == "" || a.Category == q.Get("category")) {
+				matches = append(matches, a)
+			}
+		}
+
+		// The API defaults to pages of 10 results, starting at page 0
+		perPage, page := 10, 0
+		if v, err := strconv.Atoi(q.Get("max_results")); err == nil {
+			perPage = v
+		}
+		if v, err := strconv.Atoi(q.Get("page")); err == nil {
+			page = v
+		}
+		start := min(page*perPage, len(matches))
+		end := min(start+perPage, len(matches))
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result":      append([]Asset{}, matches[start:end]...),
+			"page":        page,
+			"pages":       (len(matches) + perPage - 1) / perPage,
+			"total_items": len(matches),
+		})
+	})
+	mux.HandleFunc("/asset/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/asset/")
+		for _, a := range assets {
+			if a.ID == id {
+				json.NewEncoder(w).Encode(a)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/configure", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"categories": [{"id": "1", "name": "2D Tools"}, {"id": "5", "name": "Scripts"}]}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	old := APIURL
+	APIURL = server.URL
+	t.Cleanup(func() { APIURL = old })
+	return &lastQuery
+}
+
+func testAssets(n int) []Asset {
+	var assets []Asset
+	for i := 1; i <= n; i++ {
+		assets = append(assets, Asset{
+			ID:            strconv.Itoa(i),
+			Title:         fmt.Sprintf("Dialogue Tool %d", i),
+			Author:        "someone",
+			Category:      "5",
+			GodotVersion:  "4.3",
+			VersionString: "1.0." + strconv.Itoa(i),
+			License:       "MIT",
+			DownloadURL:   fmt.Sprintf("https://example.com/%d.zip", i),
+		})
+	}
+	return assets
+}
+
+func TestSearch(t *testing.T) {
+	assets := testAssets(3)
+	assets[2].Title = "Terrain"
+	assets[1].GodotVersion = "4.2"
+	lastQuery := fakeLibrary(t, assets)
+
+	results, total, err := Search(SearchOptions{Query: "dialogue", GodotVersion: "4.3", Category: "5", MaxResults: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(results) != 1 || results[0].ID != "1" {
+		t.Fatalf("got %d of %d results: %+v", len(results), total, results)
+	}
+	if r := results[0]; r.VersionString != "1.0.1" || r.License != "MIT" || r.DownloadURL != "https://example.com/1.zip" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+
+	for _, param := range []string{"type=addon", "sort=updated", "filter=dialogue", "godot_version=4.3", "category=5", "max_results=20"} {
+		if !strings.Contains(*lastQuery, param) {
+			t.Errorf("query %q is missing %s", *lastQuery, param)
+		}
+	}
+	if strings.Contains(*lastQuery, "page=") {
+		t.Errorf("first page requested explicitly: %q", *lastQuery)
+	}
+}
+
+func TestSearchPaging(t *testing.T) {
+	fakeLibrary(t, testAssets(25))
+
+	var seen []string
+	for page := 0; page < 3; page++ {
+		results, total, err := Search(SearchOptions{Query: "dialogue", MaxResults: 10, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 25 {
+			t.Errorf("page %d: total = %d, want 25", page, total)
+		}
+		if want := min(10, 25-page*10); len(results) != want {
+			t.Errorf("page %d: got %d results, want %d", page, len(results), want)
+		}
+		for _, r := range results {
+			seen = append(seen, r.ID)
+		}
+	}
+
+	if len(seen) != 25 || seen[0] != "1" || seen[10] != "11" || seen[24] != "25" {
+		t.Errorf("pages returned %v", seen)
+	}
+
+	results, total, err := Search(SearchOptions{Query: "dialogue", MaxResults: 10, Page: 3})
+	if err != nil || len(results) != 0 || total != 25 {
+		t.Errorf("past the last page: %d results of %d, %v", len(results), total, err)
+	}
+}
+
+func TestGetAsset(t *testing.T) {
+	assets := testAssets(2)
+	assets[1].DownloadURL = ""
+	fakeLibrary(t, assets)
+
+	asset, err := GetAsset("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Title != "Dialogue Tool 1" || asset.DownloadURL != "https://example.com/1.zip" {
+		t.Errorf("unexpected asset: %+v", asset)
+	}
+
+	if _, err := GetAsset("2"); err == nil || !strings.Contains(err.Error(), "no download") {
+		t.Errorf("asset without download: %v", err)
+	}
+	if _, err := GetAsset("404"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing asset: %v", err)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	fakeLibrary(t, nil)
+
+	categories, err := Categories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 2 || categories[1].ID != "5" || categories[1].Name != "Scripts" {
+		t.Errorf("got %+v", categories)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	old := APIURL
+	APIURL = server.URL
+	defer func() { APIURL = old }()
+
+	if _, _, err := Search(SearchOptions{Query: "gut"}); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Search: %v", err)
+	}
+	if _, err := GetAsset("1"); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("GetAsset: %v", err)
+	}
+	if _, err := Categories(); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Categories: %v", err)
+	}
+}
+
+func TestAPIURLFromEnvironment(t *testing.T) {
+	t.Setenv("GDCLI_ASSET_LIBRARY_URL", "http://localhost:8080/api/")
+	if got := apiURL(); got != "http://localhost:8080/api" {
+		t.Errorf("apiURL() = %q", got)
+	}
+
+	t.Setenv("GDCLI_ASSET_LIBRARY_URL", "")
+	if got := apiURL(); got != DefaultAPIURL {
+		t.Errorf("apiURL() = %q, want the default", got)
+	}
+}
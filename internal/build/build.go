@@ -0,0 +1,407 @@
+// Package build compiles the Godot editor from source with scons and
+// registers the result as a custom engine.
+package build
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/semver"
+	"github.com/IgorBayerl/gdcli/internal/source"
+)
+
+// Options describes an engine build.
+type Options struct {
+	Source    string   // Git repository URL or local checkout
+	Ref       string   // Branch, tag or commit to build, the default branch or the checkout as is if empty
+	Name      string   // Name of the custom engine, derived from the commit if empty
+	SconsArgs []string // Extra arguments for scons
+	DotNet    bool     // Build with C# support
+}
+
+// SourcesDir holds the checkouts of git sources, reused between builds.
+func SourcesDir() string {
+	return filepath.Join(core.GetHomePath(), "sources")
+}
+
+// BuildsDir holds the built editors, one directory per custom engine.
+func BuildsDir() string {
+	return filepath.Join(core.GetHomePath(), "builds")
+}
+
+// UnusedBuilds returns the directories in BuildsDir no registered custom
+// engine is installed from, e.g. left behind by 'gdcli engine remove'.
+func UnusedBuilds() ([]string, error) {
+	entries, err := os.ReadDir(BuildsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	engines, err := core.CustomEngines()
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []string
+	for _, entry := range entries {
+		dir := filepath.Join(BuildsDir(), entry.Name())
+		used := false
+		for _, e := range engines {
+			if e.Path != "" && (e.Path == dir || strings.HasPrefix(e.Path, dir+string(filepath.Separator))) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			unused = append(unused, dir)
+		}
+	}
+	return unused, nil
+}
+
+// sconsPlatform is the platform name scons builds the current OS with.
+func sconsPlatform() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "windows"
+	case "darwin":
+		return "macos"
+	default:
+		return "linuxbsd"
+	}
+}
+
+// usesGit reports whether the build needs git to get the sources.
+func (o Options) usesGit() bool {
+	info, err := os.Stat(o.Source)
+	return err != nil || !info.IsDir() || o.Ref != ""
+}
+
+// tool is a program the build needs, found under any of its names.
+type tool struct {
+	names []string
+	hint  string
+}
+
+// checkToolchain returns an error listing every program the build needs but
+// cannot find, so a build does not fail halfway through.
+func checkToolchain(opts Options) error {
+	tools := []tool{
+		{[]string{"scons"}, "install it with 'pip install scons'"},
+		{[]string{"python3", "python"}, "install Python 3.8 or newer"},
+	}
+	if opts.usesGit() {
+		tools = append(tools, tool{[]string{"git"}, "install git"})
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		tools = append(tools, tool{[]string{"cl", "x86_64-w64-mingw32-g++", "g++"}, "install Visual Studio Build Tools or MinGW-w64"})
+	case "darwin":
+		tools = append(tools, tool{[]string{"clang++"}, "install the Xcode command line tools with 'xcode-select --install'"})
+	default:
+		tools = append(tools,
+			tool{[]string{"g++", "clang++"}, "install GCC or Clang, e.g. the build-essential package"},
+			tool{[]string{"pkg-config"}, "install pkg-config"},
+		)
+	}
+	if opts.DotNet {
+		tools = append(tools, tool{[]string{"dotnet"}, "install the .NET SDK"})
+	}
+
+	var missing []string
+	for _, t := range tools {
+		found := false
+		for _, name := range t.names {
+			if _, err := exec.LookPath(name); err == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("  - %s: %s", strings.Join(t.names, " or "), t.hint))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing build prerequisites:\n%s", strings.Join(missing, "\n"))
+	}
+	return nil
+}
+
+// Build checks out and compiles the editor, registers it as a custom engine
+// and installs it into the engine store.
+func Build(opts Options) (core.GodotVersion, error) {
+	if err := checkToolchain(opts); err != nil {
+		return core.GodotVersion{}, err
+	}
+	if opts.Name != "" {
+		if err := core.ValidateCustomName(opts.Name); err != nil {
+			return core.GodotVersion{}, err
+		}
+	}
+
+	dir, commit, err := checkout(opts)
+	if err != nil {
+		return core.GodotVersion{}, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "SConstruct")); err != nil {
+		return core.GodotVersion{}, fmt.Errorf("%s is not a Godot source tree, SConstruct not found", opts.Source)
+	}
+
+	name := opts.Name
+	if name == "" {
+		if commit == "" {
+			return core.GodotVersion{}, fmt.Errorf("%s is not a git checkout, choose a name for the build", opts.Source)
+		}
+		name = "godot-" + commit[:10]
+		if opts.DotNet {
+			name += "-mono"
+		}
+		if dirty(dir) {
+			fmt.Println("Warning: the checkout has uncommitted changes, the build does not match its commit")
+			name += "-dirty"
+		}
+	}
+
+	if err := scons(dir, opts); err != nil {
+		return core.GodotVersion{}, err
+	}
+
+	binary, err := findEditor(dir)
+	if err != nil {
+		return core.GodotVersion{}, err
+	}
+
+	if opts.DotNet {
+		if err := buildAssemblies(dir, binary); err != nil {
+			return core.GodotVersion{}, err
+		}
+	}
+
+	// Keep a copy, as the next build in the same checkout replaces the binary
+	buildDir := filepath.Join(BuildsDir(), name)
+	if err := os.RemoveAll(buildDir); err != nil {
+		return core.GodotVersion{}, err
+	}
+	if err := os.MkdirAll(buildDir, 0755); err != nil {
+		return core.GodotVersion{}, err
+	}
+	if err := copyBuild(binary, buildDir); err != nil {
+		return core.GodotVersion{}, fmt.Errorf("failed to copy build: %v", err)
+	}
+
+	engine, err := core.NewCustomEngine(name, "", filepath.Join(buildDir, filepath.Base(binary)), sourceVersion(dir), "", opts.DotNet)
+	if err != nil {
+		return core.GodotVersion{}, err
+	}
+	engine.Commit = commit
+
+	if err := core.AddCustomEngine(engine); err != nil {
+		return core.GodotVersion{}, fmt.Errorf("failed to register engine: %v", err)
+	}
+	// Replace an earlier build of the same name, projects using it stay linked
+	if err := core.ReinstallEngine(engine); err != nil {
+		return core.GodotVersion{}, err
+	}
+	return engine, nil
+}
+
+// copyBuild copies the editor binary and the GodotSharp assemblies of Mono
+// builds from the bin directory, which also holds other builds, to dest.
+func copyBuild(binary, dest string) error {
+	sharpDir := filepath.Join(filepath.Dir(binary), "GodotSharp")
+	if _, err := os.Stat(sharpDir); err == nil {
+		if err := core.CopyDir(sharpDir, filepath.Join(dest, "GodotSharp")); err != nil {
+			return err
+		}
+	}
+
+	in, err := os.Open(binary)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(filepath.Join(dest, filepath.Base(binary)), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// checkout returns the source tree to build and its commit. Git sources are
+// checked out in SourcesDir, a local directory is built as it is unless a ref
+// is given.
+func checkout(opts Options) (string, string, error) {
+	if !opts.usesGit() {
+		commit, _ := source.Commit(opts.Source)
+		return opts.Source, commit, nil
+	}
+
+	repo, ref, _ := source.SplitRef(opts.Source)
+	if opts.Ref != "" {
+		ref = opts.Ref
+	}
+	if info, err := os.Stat(repo); err == nil && info.IsDir() {
+		if repo, err = filepath.Abs(repo); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := os.MkdirAll(SourcesDir(), 0755); err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(repo))
+	dir := filepath.Join(SourcesDir(), hex.EncodeToString(sum[:8]))
+
+	commit, err := source.Checkout(repo, ref, dir)
+	if err != nil {
+		return "", "", err
+	}
+	return dir, commit, nil
+}
+
+func dirty(dir string) bool {
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=no").Output()
+	return err == nil && len(strings.TrimSpace(string(out))) > 0
+}
+
+func scons(dir string, opts Options) error {
+	args := []string{"platform=" + sconsPlatform(), "target=editor"}
+	if opts.DotNet {
+		args = append(args, "module_mono_enabled=yes")
+	}
+
+	jobs := true
+	for _, arg := range opts.SconsArgs {
+		if strings.HasPrefix(arg, "-j") {
+			jobs = false
+		}
+	}
+	if jobs {
+		args = append(args, fmt.Sprintf("-j%d", runtime.NumCPU()))
+	}
+	args = append(args, opts.SconsArgs...)
+
+	fmt.Printf("Running scons %s\n", strings.Join(args, " "))
+	cmd := exec.Command("scons", args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("scons failed: %v", err)
+	}
+	return nil
+}
+
+// findEditor returns the most recently built editor in the bin directory,
+// e.g. "bin/godot.linuxbsd.editor.x86_64".
+func findEditor(dir string) (string, error) {
+	prefix := "godot." + sconsPlatform() + ".editor."
+
+	matches, err := filepath.Glob(filepath.Join(dir, "bin", prefix+"*"))
+	if err != nil {
+		return "", err
+	}
+
+	var editors []string
+	for _, m := range matches {
+		name := filepath.Base(m)
+		// Windows builds come with a console wrapper, debug symbols are separate files
+		if strings.Contains(name, ".console.") || strings.HasSuffix(name, ".pdb") || strings.HasSuffix(name, ".debugsymbols") {
+			continue
+		}
+		editors = append(editors, m)
+	}
+	if len(editors) == 0 {
+		return "", fmt.Errorf("no editor binary found in %s", filepath.Join(dir, "bin"))
+	}
+
+	sort.Slice(editors, func(i, j int) bool {
+		a, _ := os.Stat(editors[i])
+		b, _ := os.Stat(editors[j])
+		return a.ModTime().After(b.ModTime())
+	})
+	return editors[0], nil
+}
+
+// buildAssemblies generates the C# glue and builds the GodotSharp assemblies
+// of a Mono build into the bin directory.
+func buildAssemblies(dir, binary string) error {
+	fmt.Println("Generating C# glue...")
+	glue := exec.Command(binary, "--headless", "--generate-mono-glue", filepath.Join("modules", "mono", "glue"))
+	glue.Dir = dir
+	glue.Stdout = os.Stdout
+	glue.Stderr = os.Stderr
+	if err := glue.Run(); err != nil {
+		return fmt.Errorf("failed to generate C# glue: %v", err)
+	}
+
+	python := "python3"
+	if _, err := exec.LookPath(python); err != nil {
+		python = "python"
+	}
+
+	fmt.Println("Building C# assemblies...")
+	assemblies := exec.Command(python, filepath.Join("modules", "mono", "build_scripts", "build_assemblies.py"), "--godot-output-dir="+filepath.Join(dir, "bin"))
+	assemblies.Dir = dir
+	assemblies.Stdout = os.Stdout
+	assemblies.Stderr = os.Stderr
+	if err := assemblies.Run(); err != nil {
+		return fmt.Errorf("failed to build C# assemblies: %v", err)
+	}
+	return nil
+}
+
+var versionField = regexp.MustCompile(`^(major|minor|patch|status)\s*=\s*"?([^"\s]+)"?`)
+
+// sourceVersion reads the engine version from version.py in the source tree,
+// e.g. "4.4.0-dev" for a development snapshot. It is empty if unknown.
+func sourceVersion(dir string) string {
+	file, err := os.Open(filepath.Join(dir, "version.py"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if m := versionField.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			fields[m[1]] = m[2]
+		}
+	}
+
+	if fields["patch"] == "" {
+		fields["patch"] = "0"
+	}
+	version := fmt.Sprintf("%s.%s.%s", fields["major"], fields["minor"], fields["patch"])
+	if status := fields["status"]; status != "" && status != "stable" {
+		version += "-" + status
+	}
+
+	// Forks may use statuses gdcli does not know
+	if _, err := semver.Parse(version); err != nil {
+		return ""
+	}
+	return version
+}
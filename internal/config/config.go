@@ -2,22 +2,38 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 )
 
 type GodotConfig struct {
-	EngineVersion string `json:"engine_version"`
-	ProjectName   string `json:"project_name"`
-	IsDotNet      bool   `json:"is_dotnet"`
+	EngineVersion string            `json:"engine_version"` // Exact version or constraint, e.g. "4.3.0", "~4.3" or ">=4.2 <4.5"
+	ProjectName   string            `json:"project_name"`
+	IsDotNet      bool              `json:"is_dotnet"`
+	Scripts       map[string]string `json:"scripts,omitempty"`       // Commands run with 'gdcli run <name>'
+	Dependencies  map[string]string `json:"dependencies,omitempty"`  // Addons installed into addons/<name>, by source
+	GlobalAddons  []string          `json:"global_addons,omitempty"` // Addons from ~/.gdcli/addons linked into addons/<name>
 }
 
+// CreateConfig writes gdproj.json for the engine version and project. An
+// existing config keeps its scripts and addons, only the engine and the
+// project settings are replaced.
 func CreateConfig(version, name string, dotnet bool) error {
-	cfg := GodotConfig{
-		EngineVersion: version,
-		ProjectName:   name,
-		IsDotNet:      dotnet,
+	cfg, err := LoadConfig()
+	if os.IsNotExist(err) {
+		cfg, err = &GodotConfig{}, nil
 	}
+	if err != nil {
+		return fmt.Errorf("failed to read the existing gdproj.json: %v", err)
+	}
+
+	cfg.EngineVersion = version
+	cfg.ProjectName = name
+	cfg.IsDotNet = dotnet
+	return SaveConfig(cfg)
+}
 
+func SaveConfig(cfg *GodotConfig) error {
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
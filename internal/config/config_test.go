@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// inProject runs the test in a new project directory.
+func inProject(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestCreateConfig(t *testing.T) {
+	inProject(t)
+
+	if err := CreateConfig("4.3.0", "game", false); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.EngineVersion != "4.3.0" || cfg.ProjectName != "game" || cfg.IsDotNet {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestCreateConfigKeepsScriptsAndAddons(t *testing.T) {
+	inProject(t)
+
+	if err := SaveConfig(&GodotConfig{
+		EngineVersion: "4.2.0",
+		ProjectName:   "old",
+		Scripts:       map[string]string{"test": "godot --headless -s test.gd"},
+		Dependencies:  map[string]string{"gut": "assetlib:1709"},
+		GlobalAddons:  []string{"debug_draw"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CreateConfig("~4.3", "game", true); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.EngineVersion != "~4.3" || cfg.ProjectName != "game" || !cfg.IsDotNet {
+		t.Errorf("settings not replaced: %+v", cfg)
+	}
+	if cfg.Scripts["test"] == "" || cfg.Dependencies["gut"] != "assetlib:1709" || len(cfg.GlobalAddons) != 1 {
+		t.Errorf("scripts or addons dropped: %+v", cfg)
+	}
+}
+
+func TestCreateConfigInvalidExisting(t *testing.T) {
+	inProject(t)
+
+	if err := os.WriteFile("gdproj.json", []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateConfig("4.3.0", "game", false); err == nil {
+		t.Error("unreadable gdproj.json was overwritten")
+	}
+	if data, _ := os.ReadFile("gdproj.json"); string(data) != "{not json" {
+		t.Errorf("gdproj.json changed to %q", data)
+	}
+}
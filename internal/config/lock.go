@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// LockFile pins the exact engine build resolved from gdproj.json, so every
+// checkout of the project installs the same binaries.
+const LockFile = "gdproj.lock"
+
+type GodotLock struct {
+	Engine *EngineLock          `json:"engine,omitempty"`
+	Addons map[string]AddonLock `json:"addons,omitempty"`
+}
+
+// EngineLock is the engine build resolved from EngineVersion and IsDotNet.
+type EngineLock struct {
+	EngineVersion string        `json:"engine_version"` // Constraint from gdproj.json the lock was resolved from
+	IsDotNet      bool          `json:"is_dotnet"`
+	Version       string        `json:"version"`
+	Tag           string        `json:"tag"`
+	Custom        string        `json:"custom,omitempty"` // Name of the custom engine, if it is one
+	Assets        []LockedAsset `json:"assets"`
+}
+
+// LockedAsset is the download of the locked build for one platform.
+type LockedAsset struct {
+	OS     string `json:"os"`
+	Arch   string `json:"arch,omitempty"`
+	URL    string `json:"url"`
+	SHA512 string `json:"sha512,omitempty"`
+}
+
+// AddonLock is the build of an addon resolved from its source in gdproj.json.
+type AddonLock struct {
+	Source  string `json:"source"`            // Source from gdproj.json the entry was resolved from
+	Version string `json:"version,omitempty"` // Git commit or Asset Library version
+	URL     string `json:"url,omitempty"`     // Resolved download of Asset Library addons
+	SHA512  string `json:"sha512,omitempty"`  // Checksum of the downloaded archive
+}
+
+// Matches reports whether the lock was resolved from the config's engine
+// settings. A nil lock matches nothing.
+func (l *EngineLock) Matches(cfg *GodotConfig) bool {
+	return l != nil && l.EngineVersion == cfg.EngineVersion && l.IsDotNet == cfg.IsDotNet
+}
+
+// Asset returns the locked download for the OS and architecture, if any.
+// Universal macOS builds run on every architecture.
+func (l *EngineLock) Asset(goos, goarch string) (LockedAsset, bool) {
+	for _, a := range l.Assets {
+		if a.OS == goos && (a.Arch == goarch || a.Arch == "universal") {
+			return a, true
+		}
+	}
+	return LockedAsset{}, false
+}
+
+func SaveLock(lock *GodotLock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(LockFile, data, 0644)
+}
+
+func LoadLock() (*GodotLock, error) {
+	data, err := os.ReadFile(LockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock GodotLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	return &lock, nil
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// UniversalArch is the architecture of macOS builds running on every Mac.
+const UniversalArch = "universal"
+
+// TargetArch is the architecture versions are selected for, as a GOARCH
+// value. It defaults to the running system and is changed with --arch.
+var TargetArch = runtime.GOARCH
+
+// archAliases maps the architecture names used by Godot downloads to GOARCH.
+var archAliases = map[string]string{
+	"amd64":   "amd64",
+	"x86_64":  "amd64",
+	"x64":     "amd64",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"386":     "386",
+	"x86_32":  "386",
+	"arm":     "arm",
+	"arm32":   "arm",
+}
+
+// ParseArch converts an architecture given by the user, as GOARCH or as
+// named by Godot downloads, e.g. "x86_64", to a GOARCH value.
+func ParseArch(arch string) (string, error) {
+	if goarch, ok := archAliases[strings.ToLower(arch)]; ok {
+		return goarch, nil
+	}
+	return "", fmt.Errorf("unknown architecture '%s', use one of x86_64, x86_32, arm64 or arm32", arch)
+}
+
+// ForPlatform reports whether the version is a build for the current OS and
+// the target architecture.
+func (v GodotVersion) ForPlatform() bool {
+	return v.OS == runtime.GOOS && (v.Arch == TargetArch || v.Arch == UniversalArch)
+}
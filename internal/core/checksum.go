@@ -0,0 +1,116 @@
+package core
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SumsFileName is the checksum list published with every godot-builds release.
+const SumsFileName = "SHA512-SUMS.txt"
+
+// ExpectedChecksum returns the SHA-512 the version's download must match. It
+// is taken from the version itself or looked up in the release's sums file.
+// An empty result means no checksum is published for the version.
+func ExpectedChecksum(version GodotVersion) (string, error) {
+	if version.SHA512 != "" {
+		return strings.ToLower(version.SHA512), nil
+	}
+	if version.SumsURL == "" {
+		return "", nil
+	}
+
+	sums, err := fetchSums(version.SumsURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch checksums: %v", err)
+	}
+
+	fileName := filepath.Base(version.URL)
+	sum, ok := sums[fileName]
+	if !ok {
+		return "", fmt.Errorf("no checksum for %s in %s", fileName, version.SumsURL)
+	}
+	return sum, nil
+}
+
+// fetchSums downloads a sums file and maps file names to checksums.
+func fetchSums(url string) (map[string]string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	return parseSums(resp.Body)
+}
+
+// parseSums reads the "<checksum>  <file>" lines written by sha512sum.
+func parseSums(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha512sum marks files hashed in binary mode with a leading '*'
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
+
+func verifyChecksum(h hash.Hash, expected, fileName string) error {
+	actual := hex.EncodeToString(h.Sum(nil))
+	if actual != strings.ToLower(expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s (the download may be truncated or tampered with)",
+			fileName, expected, actual)
+	}
+	return nil
+}
+
+// ReleaseAssets returns the manifest entries for every OS that belong to the
+// same release and variant as the version, with their checksums filled in.
+// A custom engine is its only asset, and has none when installed from a path.
+func ReleaseAssets(version GodotVersion) ([]GodotVersion, error) {
+	if version.Custom != "" {
+		if version.URL == "" {
+			return nil, nil
+		}
+		return []GodotVersion{version}, nil
+	}
+
+	var sums map[string]string
+	if version.SumsURL != "" {
+		var err error
+		sums, err = fetchSums(version.SumsURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch checksums: %v", err)
+		}
+	}
+
+	var assets []GodotVersion
+	for _, v := range VersionManifest {
+		if v.Tag != version.Tag || v.Version != version.Version || v.DotNet != version.DotNet {
+			continue
+		}
+		if v.SHA512 == "" {
+			v.SHA512 = sums[filepath.Base(v.URL)]
+		}
+		assets = append(assets, v)
+	}
+	return assets, nil
+}
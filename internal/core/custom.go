@@ -0,0 +1,157 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/semver"
+)
+
+// CustomEnginesFile lists the engines registered with 'gdcli engine add', in
+// ~/.gdcli.
+const CustomEnginesFile = "engines.json"
+
+func customEnginesPath() string {
+	return filepath.Join(GetHomePath(), CustomEnginesFile)
+}
+
+// CustomEngines returns the engines registered on this machine.
+func CustomEngines() ([]GodotVersion, error) {
+	data, err := os.ReadFile(customEnginesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var engines []GodotVersion
+	if err := json.Unmarshal(data, &engines); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", customEnginesPath(), err)
+	}
+	return engines, nil
+}
+
+func saveCustomEngines(engines []GodotVersion) error {
+	if err := os.MkdirAll(GetHomePath(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(engines, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(customEnginesPath(), data, 0644)
+}
+
+// FindCustomEngine returns the registered engine with the given name.
+func FindCustomEngine(name string) (GodotVersion, bool) {
+	engines, _ := CustomEngines()
+	for _, e := range engines {
+		if e.Custom == name {
+			return e, true
+		}
+	}
+	return GodotVersion{}, false
+}
+
+// ValidateCustomName rejects names that cannot be told apart from a version
+// constraint in gdproj.json or would be unsafe as a directory name.
+func ValidateCustomName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\ `) || name == "." || name == ".." {
+		return fmt.Errorf("invalid engine name '%s'", name)
+	}
+	if _, err := semver.ParseConstraint(name); err == nil {
+		return fmt.Errorf("engine name '%s' looks like a version, choose a name such as 'godotsteam-4.3'", name)
+	}
+	return nil
+}
+
+// NewCustomEngine returns the engine for a registration. Exactly one of url,
+// a zip archive, and path, a local engine directory or executable, is set.
+// version is the Godot version the engine is based on, if known.
+func NewCustomEngine(name, url, path, version, sha512 string, dotnet bool) (GodotVersion, error) {
+	if err := ValidateCustomName(name); err != nil {
+		return GodotVersion{}, err
+	}
+	if (url == "") == (path == "") {
+		return GodotVersion{}, fmt.Errorf("either a URL or a path is required")
+	}
+	if version != "" {
+		if _, err := semver.Parse(version); err != nil {
+			return GodotVersion{}, err
+		}
+	}
+
+	if path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return GodotVersion{}, err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return GodotVersion{}, err
+		}
+		path = abs
+	}
+
+	return GodotVersion{
+		DisplayName: name,
+		Version:     version,
+		Custom:      name,
+		DotNet:      dotnet,
+		URL:         url,
+		SHA512:      sha512,
+		Path:        path,
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+	}, nil
+}
+
+// AddCustomEngine registers the engine, replacing one of the same name.
+func AddCustomEngine(engine GodotVersion) error {
+	engines, err := CustomEngines()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, e := range engines {
+		if e.Custom == engine.Custom {
+			engines[i] = engine
+			replaced = true
+		}
+	}
+	if !replaced {
+		engines = append(engines, engine)
+	}
+	return saveCustomEngines(engines)
+}
+
+// RemoveCustomEngine removes the registration of the engine. An installed
+// copy stays in the store until uninstalled.
+func RemoveCustomEngine(name string) error {
+	engines, err := CustomEngines()
+	if err != nil {
+		return err
+	}
+
+	var kept []GodotVersion
+	for _, e := range engines {
+		if e.Custom != name {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(engines) {
+		return fmt.Errorf("no custom engine named '%s'", name)
+	}
+	return saveCustomEngines(kept)
+}
+
+// CustomEngineMissingError explains how to register a custom engine that a
+// project uses but this machine does not know.
+func CustomEngineMissingError(name string) error {
+	return fmt.Errorf("'%s' is neither a Godot version nor a custom engine registered on this machine\n💡 Register it with: gdcli engine add %s --url <zip> | --path <dir|binary>", name, name)
+}
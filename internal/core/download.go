@@ -0,0 +1,339 @@
+package core
+
+import (
+	"context"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// DownloadOptions controls how engine archives are downloaded.
+type DownloadOptions struct {
+	Retries      int           // Attempts made after the first one fails
+	Backoff      time.Duration // Wait before the first retry, doubled for each further retry
+	StallTimeout time.Duration // Time without receiving data after which an attempt is abandoned, 0 for none
+	Progress     bool          // Whether to draw a progress bar
+}
+
+// DefaultDownloadOptions is used by every download. Commands may override it
+// from flags before installing.
+var DefaultDownloadOptions = DownloadOptions{
+	Retries:      3,
+	Backoff:      2 * time.Second,
+	StallTimeout: time.Minute,
+	Progress:     term.IsTerminal(int(os.Stdout.Fd())),
+}
+
+// DownloadsDir holds partially downloaded archives so that an interrupted
+// download can be resumed by the next install.
+func DownloadsDir() string {
+	return filepath.Join(GetInstallPath(), ".downloads")
+}
+
+// StagingDir holds engines being installed until they are complete. Installs
+// that were killed leave their directory behind.
+func StagingDir() string {
+	return filepath.Join(GetInstallPath(), ".staging")
+}
+
+var (
+	// errStalled fails an attempt that received no data for the stall timeout
+	errStalled = errors.New("no data received")
+	// errBadRange fails an attempt the server resumed at another offset
+	errBadRange = errors.New("server resumed at the wrong offset")
+)
+
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("bad status: %s", e.status)
+}
+
+// retryable reports whether another attempt could succeed. Only network
+// errors, connections closed early and server-side statuses are retried.
+// Client errors such as a missing file and local errors such as a full disk
+// are final.
+func retryable(err error) bool {
+	if errors.Is(err, errStalled) || errors.Is(err, errBadRange) {
+		return true
+	}
+
+	// Checked first, the context errors are net.Errors as well
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		// A partial file not fitting the remote one is restarted from scratch
+		return statusErr.code >= 500 ||
+			statusErr.code == http.StatusRequestTimeout ||
+			statusErr.code == http.StatusTooManyRequests ||
+			statusErr.code == http.StatusRequestedRangeNotSatisfiable
+	}
+
+	// syscall.Errno is a net.Error too, errors of the local file are final
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// DownloadFile downloads url to path. The data is written to "<path>.part"
+// first, which is resumed with an HTTP Range request when a previous attempt
+// was interrupted. When expectedSHA512 is set, the download is hashed while
+// streaming and the file is deleted if the checksum does not match.
+func DownloadFile(path string, url string, expectedSHA512 string) error {
+	opts := DefaultDownloadOptions
+	partPath := path + ".part"
+	backoff := opts.Backoff
+
+	var h hash.Hash
+	var err error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("Download failed: %v\n", err)
+			fmt.Printf("Retrying in %s (attempt %d of %d)...\n", backoff, attempt, opts.Retries)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		h, err = downloadAttempt(partPath, url, opts)
+		if err == nil || !retryable(err) {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if expectedSHA512 != "" {
+		if err := verifyChecksum(h, expectedSHA512, filepath.Base(path)); err != nil {
+			os.Remove(partPath)
+			return err
+		}
+	}
+
+	return os.Rename(partPath, path)
+}
+
+// downloadAttempt appends the rest of url to partPath and returns the hash of
+// the complete file. The attempt is abandoned when no data arrives for the
+// stall timeout, however long the whole download takes.
+func downloadAttempt(partPath, url string, opts DownloadOptions) (hash.Hash, error) {
+	out, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	// Hash what was downloaded by earlier attempts before appending to it.
+	h := sha512.New()
+	offset, err := io.Copy(h, out)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	stall := newStallTimer(opts.StallTimeout, func() {
+		cancel(fmt.Errorf("%w for %s", errStalled, opts.StallTimeout))
+	})
+	defer stall.stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, attemptError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		if start, ok := rangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			// Start over on the next attempt rather than append at the wrong place
+			if err := out.Truncate(0); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: asked for byte %d, got %q", errBadRange, offset, resp.Header.Get("Content-Range"))
+		}
+		fmt.Printf("Resuming download at %s\n", FormatBytes(offset))
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range, start over.
+		if offset > 0 {
+			if err := out.Truncate(0); err != nil {
+				return nil, err
+			}
+			if _, err := out.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			h.Reset()
+			offset = 0
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file does not fit the remote one, start over on the next attempt.
+		if err := out.Truncate(0); err != nil {
+			return nil, err
+		}
+		return nil, &statusError{code: resp.StatusCode, status: resp.Status}
+	default:
+		return nil, &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+
+	var dst io.Writer = io.MultiWriter(out, h, stall)
+	if opts.Progress {
+		bar := newProgressBar(offset, offset+resp.ContentLength)
+		defer bar.finish()
+		dst = io.MultiWriter(dst, bar)
+	}
+
+	if _, err := io.Copy(dst, resp.Body); err != nil {
+		return nil, attemptError(ctx, err)
+	}
+	return h, nil
+}
+
+// attemptError returns why the attempt's request was canceled, if it was.
+func attemptError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, errStalled) {
+		return cause
+	}
+	return err
+}
+
+// rangeStart returns the first byte of a Content-Range header such as
+// "bytes 1024-2047/4096".
+func rangeStart(contentRange string) (int64, bool) {
+	start, _, ok := strings.Cut(strings.TrimPrefix(contentRange, "bytes "), "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
+
+// stallTimer calls its function when no data was written to it for the
+// timeout. A zero timeout never fires.
+type stallTimer struct {
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func newStallTimer(timeout time.Duration, f func()) *stallTimer {
+	if timeout <= 0 {
+		return &stallTimer{}
+	}
+	return &stallTimer{timer: time.AfterFunc(timeout, f), timeout: timeout}
+}
+
+func (s *stallTimer) Write(b []byte) (int, error) {
+	if s.timer != nil {
+		s.timer.Reset(s.timeout)
+	}
+	return len(b), nil
+}
+
+func (s *stallTimer) stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// progressBar draws the download progress on a single terminal line.
+type progressBar struct {
+	done      int64
+	total     int64 // Negative when the server sent no length
+	resumed   int64
+	started   time.Time
+	lastDrawn time.Time
+}
+
+func newProgressBar(offset, total int64) *progressBar {
+	if total < offset {
+		total = -1
+	}
+	return &progressBar{done: offset, total: total, resumed: offset, started: time.Now()}
+}
+
+func (p *progressBar) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if time.Since(p.lastDrawn) >= 200*time.Millisecond {
+		p.draw()
+	}
+	return len(b), nil
+}
+
+func (p *progressBar) finish() {
+	p.draw()
+	fmt.Println()
+}
+
+func (p *progressBar) draw() {
+	p.lastDrawn = time.Now()
+
+	elapsed := time.Since(p.started).Seconds()
+	var speed float64
+	if elapsed > 0 {
+		speed = float64(p.done-p.resumed) / elapsed
+	}
+
+	if p.total <= 0 {
+		fmt.Printf("\r%s  %s/s   ", FormatBytes(p.done), FormatBytes(int64(speed)))
+		return
+	}
+
+	const width = 30
+	filled := int(float64(width) * float64(p.done) / float64(p.total))
+	if filled > width {
+		filled = width
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+
+	eta := "--"
+	if speed > 0 {
+		remaining := time.Duration(float64(p.total-p.done)/speed) * time.Second
+		eta = remaining.Round(time.Second).String()
+	}
+
+	fmt.Printf("\r[%s] %s / %s  %s/s  ETA %s   ",
+		bar, FormatBytes(p.done), FormatBytes(p.total), FormatBytes(int64(speed)), eta)
+}
+
+// FormatBytes formats a size in bytes for display, e.g. "1.5 GiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
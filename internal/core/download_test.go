@@ -0,0 +1,326 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"connection closed early", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"server error", &statusError{code: http.StatusBadGateway, status: "502 Bad Gateway"}, true},
+		{"rate limited", &statusError{code: http.StatusTooManyRequests, status: "429 Too Many Requests"}, true},
+		{"not found", &statusError{code: http.StatusNotFound, status: "404 Not Found"}, false},
+		{"timeout", fmt.Errorf("get: %w", context.DeadlineExceeded), false},
+		{"canceled", context.Canceled, false},
+		{"disk full", &os.PathError{Op: "write", Path: "engine.zip.part", Err: syscall.ENOSPC}, false},
+		{"open failed", &os.PathError{Op: "open", Path: "engine.zip.part", Err: syscall.EACCES}, false},
+		{"stalled", fmt.Errorf("%w for 1m0s", errStalled), true},
+		{"resumed at another offset", fmt.Errorf("%w: asked for byte 10", errBadRange), true},
+		{"other", errors.New("checksum mismatch"), false},
+	}
+
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.want {
+			t.Errorf("retryable(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadFileDoesNotRetryLocalErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte("engine"))
+	}))
+	defer server.Close()
+
+	old := DefaultDownloadOptions
+	DefaultDownloadOptions = DownloadOptions{Retries: 3, Backoff: time.Hour}
+	defer func() { DefaultDownloadOptions = old }()
+
+	// The directory of the .part file does not exist
+	path := filepath.Join(t.TempDir(), "missing", "engine.zip")
+	if err := DownloadFile(path, server.URL, ""); err == nil {
+		t.Fatal("expected an error")
+	}
+	if requests != 0 {
+		t.Errorf("made %d requests, want none", requests)
+	}
+}
+
+func TestDownloadFileRetriesServerErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("engine"))
+	}))
+	defer server.Close()
+
+	old := DefaultDownloadOptions
+	DefaultDownloadOptions = DownloadOptions{Retries: 2, Backoff: time.Millisecond}
+	defer func() { DefaultDownloadOptions = old }()
+
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	if err := DownloadFile(path, server.URL, ""); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "engine" {
+		t.Errorf("downloaded %q", data)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+}
+
+// engineData is the content of the test downloads.
+var engineData = bytes.Repeat([]byte("godot engine "), 1000)
+
+// useDownloadOptions sets DefaultDownloadOptions for the test.
+func useDownloadOptions(t *testing.T, opts DownloadOptions) {
+	t.Helper()
+	old := DefaultDownloadOptions
+	DefaultDownloadOptions = opts
+	t.Cleanup(func() { DefaultDownloadOptions = old })
+}
+
+// partialDownload writes the first n bytes of engineData as the .part file of
+// path, as left by an interrupted download.
+func partialDownload(t *testing.T, path string, n int) {
+	t.Helper()
+	if err := os.WriteFile(path+".part", engineData[:n], 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func engineChecksum() string {
+	sum := sha512.Sum512(engineData)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkDownloaded fails unless path holds engineData and no .part file is left.
+func checkDownloaded(t *testing.T, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, engineData) {
+		t.Errorf("downloaded %d bytes, want %d", len(data), len(engineData))
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf(".part file left behind: %v", err)
+	}
+}
+
+func TestDownloadFileResumes(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{Retries: 1, Backoff: time.Millisecond})
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "engine.zip", time.Time{}, bytes.NewReader(engineData))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	partialDownload(t, path, 1000)
+
+	// The checksum covers the bytes of the earlier attempt as well
+	if err := DownloadFile(path, server.URL, engineChecksum()); err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, path)
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("requested ranges %q, want one from byte 1000", ranges)
+	}
+}
+
+func TestDownloadFileRestartsWhenRangeIgnored(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{Retries: 1, Backoff: time.Millisecond})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(engineData)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	partialDownload(t, path, 1000)
+
+	if err := DownloadFile(path, server.URL, engineChecksum()); err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, path)
+}
+
+func TestDownloadFileRestartsWhenRangeNotSatisfiable(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{Retries: 1, Backoff: time.Millisecond})
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "engine.zip", time.Time{}, bytes.NewReader(engineData))
+	}))
+	defer server.Close()
+
+	// A partial file longer than the remote one, e.g. of a replaced release
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	if err := os.WriteFile(path+".part", append(engineData, "stale"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := DownloadFile(path, server.URL, engineChecksum()); err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, path)
+	if len(ranges) != 2 || ranges[1] != "" {
+		t.Errorf("requested ranges %q, want the whole file after the 416", ranges)
+	}
+}
+
+func TestDownloadFileRejectsWrongContentRange(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{Retries: 1, Backoff: time.Millisecond})
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if r.Header.Get("Range") != "" {
+			// Resumes at byte 500 instead of the requested one
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 500-%d/%d", len(engineData)-1, len(engineData)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(engineData[500:])
+			return
+		}
+		w.Write(engineData)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	partialDownload(t, path, 1000)
+
+	if err := DownloadFile(path, server.URL, engineChecksum()); err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, path)
+	if len(ranges) != 2 || ranges[1] != "" {
+		t.Errorf("requested ranges %q, want the whole file after the wrong range", ranges)
+	}
+}
+
+func TestDownloadFileRetriesStalledDownload(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{Retries: 1, Backoff: time.Millisecond, StallTimeout: 100 * time.Millisecond})
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// Send part of the file, then hang until the client gives up
+			w.Header().Set("Content-Length", fmt.Sprint(len(engineData)))
+			w.Write(engineData[:1000])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		http.ServeContent(w, r, "engine.zip", time.Time{}, bytes.NewReader(engineData))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	if err := DownloadFile(path, server.URL, engineChecksum()); err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, path)
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+}
+
+func TestDownloadFileSlowIsNotStalled(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{StallTimeout: 100 * time.Millisecond})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Takes longer than the stall timeout, but keeps sending
+		for i := 0; i < len(engineData); i += len(engineData) / 6 {
+			w.Write(engineData[i:min(i+len(engineData)/6, len(engineData))])
+			w.(http.Flusher).Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "engine.zip")
+	if err := DownloadFile(path, server.URL, ""); err != nil {
+		t.Fatal(err)
+	}
+	checkDownloaded(t, path)
+}
+
+func TestDownloadFileStallError(t *testing.T) {
+	useDownloadOptions(t, DownloadOptions{StallTimeout: 50 * time.Millisecond})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	err := DownloadFile(filepath.Join(t.TempDir(), "engine.zip"), server.URL, "")
+	if !errors.Is(err, errStalled) || !strings.Contains(err.Error(), "no data received for 50ms") {
+		t.Errorf("DownloadFile() = %v, want a stall error", err)
+	}
+}
+
+func TestInstallChecksumMismatch(t *testing.T) {
+	setHome(t)
+	useDownloadOptions(t, DownloadOptions{Retries: 1, Backoff: time.Millisecond})
+
+	// The sums file lists the checksum of other bytes than the ones served
+	sum := sha512.Sum512([]byte("the published engine"))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+SumsFileName, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  Godot_v4.3-stable_linux.x86_64.zip\n", hex.EncodeToString(sum[:]))
+	})
+	mux.HandleFunc("/Godot_v4.3-stable_linux.x86_64.zip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(engineData)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	version := GodotVersion{
+		DisplayName: "4.3.0 (Standard)",
+		Version:     "4.3.0",
+		Tag:         "4.3-stable",
+		OS:          "linux",
+		Arch:        "amd64",
+		URL:         server.URL + "/Godot_v4.3-stable_linux.x86_64.zip",
+		SumsURL:     server.URL + "/" + SumsFileName,
+	}
+	err := ReinstallEngine(version)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("ReinstallEngine() = %v, want a checksum mismatch", err)
+	}
+
+	zipPath := filepath.Join(DownloadsDir(), "Godot_v4.3-stable_linux.x86_64.zip")
+	for _, path := range []string{zipPath, zipPath + ".part"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s left behind: %v", filepath.Base(path), err)
+		}
+	}
+	if IsEngineInstalled(version) {
+		t.Error("engine installed despite the checksum mismatch")
+	}
+}
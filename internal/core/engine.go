@@ -9,7 +9,12 @@ const (
 	VersionCacheFile = "versions.json"
 )
 
-func GetInstallPath() string {
+// GetHomePath returns the directory holding all of gdcli's global state.
+func GetHomePath() string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".gdcli", "versions")
+	return filepath.Join(home, ".gdcli")
+}
+
+func GetInstallPath() string {
+	return filepath.Join(GetHomePath(), "versions")
 }
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/archive"
+	"github.com/IgorBayerl/gdcli/internal/semver"
+)
+
+// ExportTemplatesFileName returns the name of the version's export templates
+// download, e.g. "Godot_v4.3-stable_mono_export_templates.tpz".
+func ExportTemplatesFileName(version GodotVersion) string {
+	variant := ""
+	if version.DotNet {
+		variant = "_mono"
+	}
+	return fmt.Sprintf("Godot_v%s%s_export_templates.tpz", version.Tag, variant)
+}
+
+// exportTemplatesRoot is the directory the editor looks for export templates
+// in, holding one directory per engine version. Godot 3 names it "templates",
+// Godot 4 "export_templates".
+func exportTemplatesRoot(major int) (string, error) {
+	name := "export_templates"
+	if major < 4 {
+		name = "templates"
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("APPDATA is not set")
+		}
+		return filepath.Join(appData, "Godot", name), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support", "Godot", name), nil
+	default:
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		return filepath.Join(dataHome, "godot", name), nil
+	}
+}
+
+// ExportTemplatesDir returns the directory the editor expects the version's
+// export templates in, e.g. ".../export_templates/4.3.stable.mono".
+func ExportTemplatesDir(version GodotVersion) (string, error) {
+	name := templatesVersion(version)
+	if name == "" {
+		return "", fmt.Errorf("the Godot version of %s is unknown, register it with --version", version.DisplayName)
+	}
+
+	major, _ := strconv.Atoi(strings.SplitN(name, ".", 2)[0])
+	root, err := exportTemplatesRoot(major)
+	if err != nil {
+		return "", err
+	}
+
+	if version.DotNet {
+		name += ".mono"
+	}
+	return filepath.Join(root, name), nil
+}
+
+// templatesVersion returns the version string of the editor that its export
+// templates directory is named after, e.g. "4.3.stable", "4.4.rc2" or
+// "4.4.dev" for a source build. It is empty if the version is unknown.
+func templatesVersion(version GodotVersion) string {
+	if version.Tag != "" {
+		return strings.Replace(version.Tag, "-", ".", 1)
+	}
+
+	v, err := semver.Parse(version.Version)
+	if err != nil {
+		return ""
+	}
+	name := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.Patch != 0 {
+		name += fmt.Sprintf(".%d", v.Patch)
+	}
+	name += "." + v.Status
+	if v.StatusNum != 0 {
+		name += strconv.Itoa(v.StatusNum)
+	}
+	return name
+}
+
+// ExportTemplatesPublished reports whether export templates can be downloaded
+// for the version. Custom engines and source builds have none.
+func ExportTemplatesPublished(version GodotVersion) bool {
+	return version.Custom == "" && version.Tag != "" && version.URL != ""
+}
+
+// ExportTemplatesInstalled reports whether the version's export templates are
+// installed where the editor looks for them.
+func ExportTemplatesInstalled(version GodotVersion) bool {
+	dir, err := ExportTemplatesDir(version)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, "version.txt"))
+	return err == nil
+}
+
+// InstallExportTemplates downloads the version's export templates from the
+// same release as the engine and unpacks them for the editor.
+func InstallExportTemplates(version GodotVersion) error {
+	if !ExportTemplatesPublished(version) {
+		return fmt.Errorf("no export templates are published for %s", version.DisplayName)
+	}
+
+	targetDir, err := ExportTemplatesDir(version)
+	if err != nil {
+		return err
+	}
+
+	if ExportTemplatesInstalled(version) {
+		fmt.Printf("Export templates for %s are already installed in %s\n", version.DisplayName, targetDir)
+		return nil
+	}
+
+	// Templates are published next to the engine downloads of the release
+	releaseURL := version.URL[:strings.LastIndex(version.URL, "/")+1]
+	fileName := ExportTemplatesFileName(version)
+	templates := GodotVersion{
+		URL:     releaseURL + fileName,
+		SumsURL: version.SumsURL,
+	}
+	if templates.SumsURL == "" {
+		templates.SumsURL = releaseURL + SumsFileName
+	}
+
+	checksum, err := ExpectedChecksum(templates)
+	if err != nil {
+		return err
+	}
+	if checksum == "" {
+		fmt.Printf("Warning: no checksum published for %s, skipping verification\n", fileName)
+	}
+
+	if err := os.MkdirAll(DownloadsDir(), 0755); err != nil {
+		return err
+	}
+	tpzPath := filepath.Join(DownloadsDir(), fileName)
+
+	fmt.Printf("Downloading %s...\n", fileName)
+	if err := DownloadFile(tpzPath, templates.URL, checksum); err != nil {
+		return err
+	}
+
+	// Extract next to the target so it can be moved into place in one rename
+	root := filepath.Dir(targetDir)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	tempDir := targetDir + ".tmp"
+	defer removeOnInterrupt(tempDir)()
+	defer os.RemoveAll(tempDir)
+
+	fmt.Printf("Extracting %s...\n", fileName)
+	if err := os.RemoveAll(tempDir); err != nil {
+		return err
+	}
+	if err := archive.ExtractZip(tpzPath, tempDir); err != nil {
+		return err
+	}
+
+	// The archive holds a single "templates" directory with a version.txt
+	extracted := filepath.Join(tempDir, "templates")
+	if _, err := os.Stat(filepath.Join(extracted, "version.txt")); err != nil {
+		return fmt.Errorf("%s does not contain export templates", fileName)
+	}
+
+	if err := uninterrupted(func() error {
+		return swapDir(extracted, targetDir, filepath.Join(tempDir, "previous"))
+	}); err != nil {
+		return fmt.Errorf("failed to move export templates: %v", err)
+	}
+
+	if err := os.Remove(tpzPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %v", fileName, err)
+	}
+
+	fmt.Printf("Installed export templates for %s in %s\n", version.DisplayName, targetDir)
+	return nil
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportTemplatesDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("APPDATA", t.TempDir())
+
+	tests := []struct {
+		version GodotVersion
+		want    string
+	}{
+		{GodotVersion{Version: "4.3.0", Tag: "4.3-stable"}, "4.3.stable"},
+		{GodotVersion{Version: "4.2.2", Tag: "4.2.2-stable", DotNet: true}, "4.2.2.stable.mono"},
+		{GodotVersion{Version: "4.4.0-rc2", Tag: "4.4-rc2"}, "4.4.rc2"},
+		{GodotVersion{Custom: "steam", Version: "4.3.0"}, "4.3.stable"},
+		{GodotVersion{Custom: "steam", Version: "4.3.1", DotNet: true}, "4.3.1.stable.mono"},
+		{GodotVersion{Custom: "master", Version: "4.4.0-dev"}, "4.4.dev"},
+		{GodotVersion{Version: "3.5.3", Tag: "3.5.3-stable"}, "3.5.3.stable"},
+	}
+
+	for _, tt := range tests {
+		dir, err := ExportTemplatesDir(tt.version)
+		if err != nil {
+			t.Errorf("ExportTemplatesDir(%+v): %v", tt.version, err)
+			continue
+		}
+		if filepath.Base(dir) != tt.want {
+			t.Errorf("ExportTemplatesDir(%+v) = %s, want it named %s", tt.version, dir, tt.want)
+		}
+	}
+
+	if _, err := ExportTemplatesDir(GodotVersion{DisplayName: "steam", Custom: "steam"}); err == nil {
+		t.Error("custom engine without a version has a templates directory")
+	}
+}
+
+func TestInstallExportTemplatesCustomEngine(t *testing.T) {
+	setHome(t)
+	engine := GodotVersion{DisplayName: "steam", Custom: "steam", Version: "4.3.0", URL: "https://example.com/steam.zip"}
+	if ExportTemplatesPublished(engine) {
+		t.Error("custom engine reported as having published templates")
+	}
+	if err := InstallExportTemplates(engine); err == nil || !strings.Contains(err.Error(), "no export templates") {
+		t.Errorf("InstallExportTemplates(custom) = %v", err)
+	}
+}
+
+func TestExportTemplatesRootByMajorVersion(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("APPDATA", t.TempDir())
+
+	tests := []struct {
+		version GodotVersion
+		root    string
+	}{
+		{GodotVersion{Version: "3.5.3", Tag: "3.5.3-stable"}, "templates"},
+		{GodotVersion{Version: "3.6.0", Tag: "3.6-stable", DotNet: true}, "templates"},
+		{GodotVersion{Custom: "old", Version: "3.5.0"}, "templates"},
+		{GodotVersion{Version: "4.0.0", Tag: "4.0-stable"}, "export_templates"},
+		{GodotVersion{Version: "4.3.0", Tag: "4.3-stable"}, "export_templates"},
+	}
+
+	for _, tt := range tests {
+		dir, err := ExportTemplatesDir(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root := filepath.Base(filepath.Dir(dir)); root != tt.root {
+			t.Errorf("ExportTemplatesDir(%+v) = %s, want it in %s", tt.version, dir, tt.root)
+		}
+	}
+}
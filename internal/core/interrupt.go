@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var (
+	interruptMu    sync.Mutex
+	interruptPaths = map[string]bool{}
+	interruptOnce  sync.Once
+)
+
+// removeOnInterrupt registers a temporary path to be removed when gdcli is
+// interrupted, e.g. with Ctrl-C, before it exits. The returned function
+// unregisters the path again.
+func removeOnInterrupt(path string) func() {
+	interruptOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+
+			// Waits for a running uninterrupted step to finish
+			interruptMu.Lock()
+			for p := range interruptPaths {
+				os.RemoveAll(p)
+			}
+			fmt.Println("\nInterrupted, removed temporary files")
+			os.Exit(130)
+		}()
+	})
+
+	interruptMu.Lock()
+	interruptPaths[path] = true
+	interruptMu.Unlock()
+
+	return func() {
+		interruptMu.Lock()
+		delete(interruptPaths, path)
+		interruptMu.Unlock()
+	}
+}
+
+// uninterrupted runs fn to completion before an interrupt removes temporary
+// paths, e.g. while a staged install is swapped in.
+func uninterrupted(fn func() error) error {
+	interruptMu.Lock()
+	defer interruptMu.Unlock()
+	return fn()
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+const (
+	// LegacyExecutable is the name engines were renamed to before the layout
+	// of an install was recorded.
+	LegacyExecutable = "godot.exe"
+
+	legacyConsole = "godot_console.exe"
+
+	// sharpDir holds the .NET assemblies next to the executable of mono builds.
+	sharpDir = "GodotSharp"
+)
+
+// EngineLayout records where the files of an installed engine are, relative
+// to its directory in the store.
+type EngineLayout struct {
+	Executable string `json:"executable"`          // Main executable, e.g. "Godot_v4.3-stable_linux.x86_64" or "Godot.app/Contents/MacOS/Godot"
+	Console    string `json:"console,omitempty"`   // Console wrapper of Windows builds
+	SharpDir   string `json:"sharp_dir,omitempty"` // GodotSharp directory of mono builds
+}
+
+// layout returns the recorded layout of an installed version, or the one of
+// installs that renamed their executables to godot.exe.
+func (v GodotVersion) layout() EngineLayout {
+	if v.Layout != nil {
+		return *v.Layout
+	}
+
+	legacy := EngineLayout{Executable: LegacyExecutable}
+	if runtime.GOOS == "windows" {
+		legacy.Console = legacyConsole
+	}
+	return legacy
+}
+
+// EnginePath returns the path of the main executable of the linked engine.
+func (e *ProjectEngine) EnginePath() string {
+	return filepath.Join(e.Path, e.Version.layout().Executable)
+}
+
+// ConsolePath returns the path of the executable to run the linked engine
+// from a terminal with. Only Windows builds have a separate one, elsewhere
+// it is the main executable.
+func (e *ProjectEngine) ConsolePath() string {
+	if console := e.Version.layout().Console; console != "" {
+		return filepath.Join(e.Path, console)
+	}
+	return e.EnginePath()
+}
+
+// SharpPath returns the GodotSharp directory of the linked engine, or "" if
+// it is not a mono build.
+func (e *ProjectEngine) SharpPath() string {
+	if dir := e.Version.layout().SharpDir; dir != "" {
+		return filepath.Join(e.Path, dir)
+	}
+	return ""
+}
+
+// linuxArchSuffixes maps GOARCH to the extensions of Linux executables, e.g.
+// "Godot_v4.3-stable_linux.x86_64", "godot.linuxbsd.editor.arm64" or
+// "Godot_v3.5.3-stable_x11.64" of Godot 3.
+var linuxArchSuffixes = map[string][]string{
+	"amd64": {".x86_64", ".64"},
+	"arm64": {".arm64"},
+	"386":   {".x86_32", ".32"},
+	"arm":   {".arm32"},
+}
+
+// engineExecutables picks the main and console executable of an engine for
+// a platform from the names of the files in a directory. The names are
+// empty if there is none.
+func engineExecutables(goos, goarch string, names []string) (main, console string) {
+	for _, name := range names {
+		lowerName := strings.ToLower(name)
+
+		switch goos {
+		case "windows":
+			// Official builds have "_console", builds from source ".console"
+			if !strings.HasSuffix(lowerName, ".exe") {
+				continue
+			}
+			if strings.Contains(lowerName, "_console") || strings.Contains(lowerName, ".console.") {
+				console = name
+			} else {
+				main = name
+			}
+		case "darwin":
+			// Builds from source outside of a bundle, e.g. "godot.macos.editor.arm64"
+			ext := filepath.Ext(lowerName)
+			if strings.HasPrefix(lowerName, "godot") && (ext == "."+UniversalArch || slices.Contains(linuxArchSuffixes[goarch], ext)) {
+				main = name
+			}
+		default:
+			if strings.HasPrefix(lowerName, "godot") && slices.Contains(linuxArchSuffixes[goarch], filepath.Ext(lowerName)) {
+				main = name
+			}
+		}
+	}
+	return main, console
+}
+
+// appExecutable returns the executable inside a macOS .app bundle in dir,
+// e.g. "Godot.app/Contents/MacOS/Godot", and the bundle. Both are empty if
+// dir has no bundle.
+func appExecutable(dir string, entries []os.DirEntry) (executable, bundle string) {
+	for _, entry := range entries {
+		lowerName := strings.ToLower(entry.Name())
+		if !entry.IsDir() || !strings.HasPrefix(lowerName, "godot") || filepath.Ext(lowerName) != ".app" {
+			continue
+		}
+
+		macOSDir := filepath.Join(entry.Name(), "Contents", "MacOS")
+		binaries, err := os.ReadDir(filepath.Join(dir, macOSDir))
+		if err != nil {
+			continue
+		}
+		for _, binary := range binaries {
+			if !binary.IsDir() && strings.HasPrefix(strings.ToLower(binary.Name()), "godot") {
+				return filepath.Join(macOSDir, binary.Name()), entry.Name()
+			}
+		}
+	}
+	return "", ""
+}
+
+// detectLayout finds the executables of the version's engine in dir without
+// changing anything. macOS engines are kept as the Godot.app bundle of the
+// download.
+func detectLayout(dir string, version GodotVersion) (EngineLayout, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return EngineLayout{}, err
+	}
+
+	var layout EngineLayout
+	var bundle string
+	if version.OS == "darwin" {
+		layout.Executable, bundle = appExecutable(dir, entries)
+	}
+
+	if layout.Executable == "" {
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		layout.Executable, layout.Console = engineExecutables(version.OS, version.Arch, names)
+	}
+
+	if layout.Executable == "" {
+		return EngineLayout{}, fmt.Errorf("no Godot executable for %s/%s found in %s", version.OS, version.Arch, dir)
+	}
+
+	// Bundles keep the assemblies of mono builds in their resources
+	sharpPath := sharpDir
+	if bundle != "" {
+		sharpPath = filepath.Join(bundle, "Contents", "Resources", sharpDir)
+	}
+	if info, err := os.Stat(filepath.Join(dir, sharpPath)); err == nil && info.IsDir() {
+		layout.SharpDir = sharpPath
+	}
+	return layout, nil
+}
+
+// installedLayout detects the layout of the engine installed into dir and
+// makes its executable runnable, as builds from source may lack the bit.
+func installedLayout(dir string, version GodotVersion) (EngineLayout, error) {
+	layout, err := detectLayout(dir, version)
+	if err != nil {
+		return EngineLayout{}, err
+	}
+
+	if version.OS != "windows" {
+		if err := os.Chmod(filepath.Join(dir, layout.Executable), 0755); err != nil {
+			return EngineLayout{}, err
+		}
+	}
+	return layout, nil
+}
+
+var errFound = fmt.Errorf("found")
+
+// findEngineDir returns the directory below searchDir holding the engine
+// executables, e.g. the top directory of a mono download.
+func findEngineDir(searchDir string, version GodotVersion) (string, error) {
+	var foundDir string
+
+	err := filepath.WalkDir(searchDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		if _, err := detectLayout(path, version); err == nil {
+			foundDir = path
+			// Return a sentinel error to stop the walk.
+			return errFound
+		}
+		return nil
+	})
+
+	// If we broke out because we found the exe, ignore the sentinel error.
+	if err != nil && err != errFound {
+		return "", err
+	}
+	if foundDir == "" {
+		return "", fmt.Errorf("no Godot executable for %s/%s found in %s", version.OS, version.Arch, searchDir)
+	}
+	return foundDir, nil
+}
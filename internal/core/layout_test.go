@@ -0,0 +1,162 @@
+package core
+
+import (
+	"archive/zip"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEngineExecutables(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		names        []string
+		main         string
+		console      string
+	}{
+		{"linux", "amd64", []string{"Godot_v4.3-stable_linux.x86_64"}, "Godot_v4.3-stable_linux.x86_64", ""},
+		{"linux", "arm64", []string{"Godot_v4.3-stable_linux.arm64"}, "Godot_v4.3-stable_linux.arm64", ""},
+		{"linux", "386", []string{"Godot_v4.3-stable_linux.x86_32"}, "Godot_v4.3-stable_linux.x86_32", ""},
+		{"linux", "arm", []string{"Godot_v4.3-stable_linux.arm32"}, "Godot_v4.3-stable_linux.arm32", ""},
+		{"linux", "amd64", []string{"Godot_v3.5.3-stable_x11.64"}, "Godot_v3.5.3-stable_x11.64", ""},
+		{"linux", "386", []string{"Godot_v3.5.3-stable_x11.32"}, "Godot_v3.5.3-stable_x11.32", ""},
+		{"linux", "amd64", []string{"Godot_v3.5.3-stable_mono_x11.64"}, "Godot_v3.5.3-stable_mono_x11.64", ""},
+		{"linux", "amd64", []string{"godot.linuxbsd.editor.x86_64"}, "godot.linuxbsd.editor.x86_64", ""},
+		{"linux", "amd64", []string{"Godot_v4.3-stable_linux.arm64"}, "", ""},
+		{"linux", "arm64", []string{"Godot_v3.5.3-stable_x11.64"}, "", ""},
+		{"linux", "amd64", []string{"README.txt", "icon.64"}, "", ""},
+		{"windows", "amd64", []string{"Godot_v4.3-stable_win64.exe", "Godot_v4.3-stable_win64_console.exe"},
+			"Godot_v4.3-stable_win64.exe", "Godot_v4.3-stable_win64_console.exe"},
+		{"windows", "amd64", []string{"godot.windows.editor.x86_64.exe", "godot.windows.editor.x86_64.console.exe"},
+			"godot.windows.editor.x86_64.exe", "godot.windows.editor.x86_64.console.exe"},
+		{"darwin", "arm64", []string{"godot.macos.editor.arm64"}, "godot.macos.editor.arm64", ""},
+		{"darwin", "amd64", []string{"godot.macos.editor.universal"}, "godot.macos.editor.universal", ""},
+	}
+
+	for _, tt := range tests {
+		main, console := engineExecutables(tt.goos, tt.goarch, tt.names)
+		if main != tt.main || console != tt.console {
+			t.Errorf("engineExecutables(%s, %s, %v) = %q, %q, want %q, %q",
+				tt.goos, tt.goarch, tt.names, main, console, tt.main, tt.console)
+		}
+	}
+}
+
+// macOSZip writes a zip laid out like the macOS downloads, with the engine
+// inside Godot.app, and returns its path.
+func macOSZip(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Godot_v4.3-stable_mono_macos.universal.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	files := []struct {
+		name string
+		mode os.FileMode
+	}{
+		{"Godot_mono.app/Contents/Info.plist", 0644},
+		{"Godot_mono.app/Contents/MacOS/Godot", 0755},
+		{"Godot_mono.app/Contents/Resources/GodotSharp/Api/GodotSharp.dll", 0644},
+	}
+	for _, file := range files {
+		header := &zip.FileHeader{Name: file.name, Method: zip.Deflate}
+		header.SetMode(file.mode)
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte("content of " + file.name))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInstallMacOSBundle(t *testing.T) {
+	setHome(t)
+	zipPath := macOSZip(t)
+	data, err := os.ReadFile(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha512.Sum512(data)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, zipPath)
+	}))
+	defer server.Close()
+
+	version := GodotVersion{
+		DisplayName: "4.3.0 (Mono)",
+		Version:     "4.3.0",
+		Tag:         "4.3-stable",
+		DotNet:      true,
+		OS:          "darwin",
+		Arch:        UniversalArch,
+		URL:         server.URL + "/" + filepath.Base(zipPath),
+		SHA512:      hex.EncodeToString(sum[:]),
+	}
+	if err := ReinstallEngine(version); err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := installedEngine(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := installed.layout()
+	bundle := "Godot_mono.app"
+	if want := filepath.Join(bundle, "Contents", "MacOS", "Godot"); layout.Executable != want {
+		t.Errorf("Executable = %q, want %q", layout.Executable, want)
+	}
+	if want := filepath.Join(bundle, "Contents", "Resources", "GodotSharp"); layout.SharpDir != want {
+		t.Errorf("SharpDir = %q, want %q", layout.SharpDir, want)
+	}
+
+	info, err := os.Stat(filepath.Join(EngineDir(version), layout.Executable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		t.Errorf("executable mode = %v, want it executable", info.Mode())
+	}
+	if _, err := os.Stat(filepath.Join(EngineDir(version), bundle, "Contents", "Info.plist")); err != nil {
+		t.Errorf("bundle not kept whole: %v", err)
+	}
+}
+
+func TestDetectLayoutOnlyProbes(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "godot.linuxbsd.editor.x86_64")
+	if err := os.WriteFile(exe, []byte("engine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	version := GodotVersion{OS: "linux", Arch: "amd64"}
+
+	if _, err := findEngineDir(dir, version); err != nil {
+		t.Fatal(err)
+	}
+	layout, err := detectLayout(dir, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm() != 0644 {
+		t.Errorf("probing changed the mode to %v", info.Mode())
+	}
+
+	if _, err := installedLayout(dir, version); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, layout.Executable)); info.Mode().Perm() != 0755 {
+		t.Errorf("installed executable mode = %v, want 0755", info.Mode())
+	}
+}
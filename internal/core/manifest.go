@@ -0,0 +1,265 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ReleasesAPIURL is the GitHub API endpoint listing the godot-builds releases.
+// It is a variable so it can be pointed at a local server.
+var ReleasesAPIURL = "https://api.github.com/repos/godotengine/godot-builds/releases"
+
+// ManifestCacheTTL is how long a fetched manifest is used before refetching.
+var ManifestCacheTTL = 24 * time.Hour
+
+const (
+	releasesPerPage = 100
+	maxReleasePages = 10
+)
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+type manifestCache struct {
+	FetchedAt time.Time      `json:"fetched_at"`
+	Versions  []GodotVersion `json:"versions"`
+}
+
+// RefreshManifest replaces VersionManifest with the release list from the
+// cache, or from the GitHub API when the cache is missing or stale. On failure
+// it falls back to a stale cache or the built-in list and returns an error
+// describing the fallback, which callers may print as a warning.
+func RefreshManifest() error {
+	cache, cacheErr := readManifestCache()
+	if cacheErr == nil && time.Since(cache.FetchedAt) < ManifestCacheTTL {
+		VersionManifest = cache.Versions
+		return nil
+	}
+
+	versions, err := FetchManifest()
+	if err == nil {
+		VersionManifest = versions
+		if err := writeManifestCache(versions); err != nil {
+			return fmt.Errorf("failed to cache version list: %v", err)
+		}
+		return nil
+	}
+
+	if cacheErr == nil {
+		VersionManifest = cache.Versions
+		return fmt.Errorf("could not fetch version list, using cached list from %s: %v",
+			cache.FetchedAt.Format("2006-01-02"), err)
+	}
+
+	VersionManifest = builtinManifest
+	return fmt.Errorf("could not fetch version list, using built-in list: %v", err)
+}
+
+// FetchManifest builds the version list from every release published on
+// godot-builds, newest first.
+func FetchManifest() ([]GodotVersion, error) {
+	var versions []GodotVersion
+
+	for page := 1; page <= maxReleasePages; page++ {
+		releases, err := fetchReleasePage(page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range releases {
+			versions = append(versions, versionsFromRelease(release)...)
+		}
+
+		if len(releases) < releasesPerPage {
+			break
+		}
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no versions found in releases")
+	}
+	return versions, nil
+}
+
+func fetchReleasePage(page int) ([]githubRelease, error) {
+	url := fmt.Sprintf("%s?per_page=%d&page=%d", ReleasesAPIURL, releasesPerPage, page)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "gdcli")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %v", err)
+	}
+	return releases, nil
+}
+
+func versionsFromRelease(release githubRelease) []GodotVersion {
+	version := versionFromTag(release.TagName)
+	if version == "" {
+		return nil
+	}
+
+	var sumsURL string
+	for _, asset := range release.Assets {
+		if asset.Name == SumsFileName {
+			sumsURL = asset.BrowserDownloadURL
+		}
+	}
+
+	var versions []GodotVersion
+	prefix := "Godot_v" + release.TagName + "_"
+	for _, asset := range release.Assets {
+		if !strings.HasPrefix(asset.Name, prefix) {
+			continue
+		}
+
+		goos, arch, dotnet, ok := parseAssetPlatform(strings.TrimPrefix(asset.Name, prefix))
+		if !ok {
+			continue
+		}
+
+		variant := "Standard"
+		if dotnet {
+			variant = "Mono"
+		}
+
+		versions = append(versions, GodotVersion{
+			DisplayName: fmt.Sprintf("%s (%s)", version, variant),
+			Version:     version,
+			Tag:         release.TagName,
+			DotNet:      dotnet,
+			URL:         asset.BrowserDownloadURL,
+			SumsURL:     sumsURL,
+			OS:          goos,
+			Arch:        arch,
+		})
+	}
+	return versions
+}
+
+// versionFromTag converts a release tag like "4.3-stable" or "4.4-rc2" into
+// the version format used in the manifest, "4.3.0" or "4.4.0-rc2".
+func versionFromTag(tag string) string {
+	number, status, ok := strings.Cut(tag, "-")
+	if !ok || number == "" {
+		return ""
+	}
+
+	parts := strings.Split(number, ".")
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	version := strings.Join(parts, ".")
+
+	if status != "stable" {
+		version += "-" + status
+	}
+	return version
+}
+
+// assetPlatforms maps the platform part of an editor archive name, with
+// underscores normalized to dots, to GOOS and GOARCH values.
+var assetPlatforms = map[string][2]string{
+	"win64.exe":         {"windows", "amd64"},
+	"win64":             {"windows", "amd64"},
+	"win32.exe":         {"windows", "386"},
+	"win32":             {"windows", "386"},
+	"windows.arm64.exe": {"windows", "arm64"},
+	"windows.arm64":     {"windows", "arm64"},
+	"linux.x86.64":      {"linux", "amd64"},
+	"linux.x86.32":      {"linux", "386"},
+	"linux.arm64":       {"linux", "arm64"},
+	"linux.arm32":       {"linux", "arm"},
+	"x11.64":            {"linux", "amd64"},
+	"x11.32":            {"linux", "386"},
+	"macos.universal":   {"darwin", "universal"},
+	"osx.universal":     {"darwin", "universal"},
+	"osx.64":            {"darwin", "amd64"},
+}
+
+// parseAssetPlatform parses the part of an editor archive name following the
+// tag, e.g. "mono_linux_x86_64.zip". Export templates, web and android
+// editors and other non-editor assets are rejected.
+func parseAssetPlatform(name string) (goos, arch string, dotnet, ok bool) {
+	if !strings.HasSuffix(name, ".zip") {
+		return "", "", false, false
+	}
+	name = strings.TrimSuffix(name, ".zip")
+
+	if strings.HasPrefix(name, "mono_") {
+		dotnet = true
+		name = strings.TrimPrefix(name, "mono_")
+	}
+
+	platform, found := assetPlatforms[strings.ReplaceAll(name, "_", ".")]
+	if !found {
+		return "", "", false, false
+	}
+	return platform[0], platform[1], dotnet, true
+}
+
+func manifestCachePath() string {
+	return filepath.Join(GetInstallPath(), VersionCacheFile)
+}
+
+func readManifestCache() (*manifestCache, error) {
+	data, err := os.ReadFile(manifestCachePath())
+	if err != nil {
+		return nil, err
+	}
+
+	var cache manifestCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	if len(cache.Versions) == 0 {
+		return nil, fmt.Errorf("version cache is empty")
+	}
+	return &cache, nil
+}
+
+func writeManifestCache(versions []GodotVersion) error {
+	data, err := json.MarshalIndent(manifestCache{
+		FetchedAt: time.Now(),
+		Versions:  versions,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(GetInstallPath(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(manifestCachePath(), data, 0644)
+}
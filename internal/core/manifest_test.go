@@ -0,0 +1,248 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setHome points the gdcli home directory at a temporary directory.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+// releasesServer serves the releases as a paginated GitHub releases API and
+// points ReleasesAPIURL at it. It counts the requests it receives.
+func releasesServer(t *testing.T, releases []githubRelease) *int32 {
+	t.Helper()
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		if page < 1 || perPage < 1 {
+			http.Error(w, "bad paging", http.StatusBadRequest)
+			return
+		}
+
+		start := min((page-1)*perPage, len(releases))
+		end := min(start+perPage, len(releases))
+		json.NewEncoder(w).Encode(releases[start:end])
+	}))
+	t.Cleanup(server.Close)
+
+	useReleasesAPI(t, server.URL)
+	return &requests
+}
+
+// useReleasesAPI points ReleasesAPIURL at url and restores it and
+// VersionManifest after the test.
+func useReleasesAPI(t *testing.T, url string) {
+	t.Helper()
+	oldURL, oldManifest := ReleasesAPIURL, VersionManifest
+	ReleasesAPIURL = url
+	t.Cleanup(func() {
+		ReleasesAPIURL, VersionManifest = oldURL, oldManifest
+	})
+}
+
+func release(tag string, platforms ...string) githubRelease {
+	r := githubRelease{TagName: tag}
+	for _, p := range platforms {
+		name := "Godot_v" + tag + "_" + p
+		r.Assets = append(r.Assets, githubAsset{Name: name, BrowserDownloadURL: "https://example.com/" + name})
+	}
+	r.Assets = append(r.Assets, githubAsset{Name: SumsFileName, BrowserDownloadURL: "https://example.com/" + tag + "/" + SumsFileName})
+	return r
+}
+
+func TestParseAssetPlatform(t *testing.T) {
+	tests := []struct {
+		name   string
+		goos   string
+		arch   string
+		dotnet bool
+		ok     bool
+	}{
+		{"linux.x86_64.zip", "linux", "amd64", false, true},
+		{"linux.x86_32.zip", "linux", "386", false, true},
+		{"linux.arm64.zip", "linux", "arm64", false, true},
+		{"linux.arm32.zip", "linux", "arm", false, true},
+		{"mono_linux_x86_64.zip", "linux", "amd64", true, true},
+		{"mono_linux_arm64.zip", "linux", "arm64", true, true},
+		{"x11.64.zip", "linux", "amd64", false, true},
+		{"x11.32.zip", "linux", "386", false, true},
+		{"win64.exe.zip", "windows", "amd64", false, true},
+		{"win32.exe.zip", "windows", "386", false, true},
+		{"windows_arm64.exe.zip", "windows", "arm64", false, true},
+		{"mono_win64.zip", "windows", "amd64", true, true},
+		{"macos.universal.zip", "darwin", "universal", false, true},
+		{"mono_macos.universal.zip", "darwin", "universal", true, true},
+		{"osx.universal.zip", "darwin", "universal", false, true},
+		{"export_templates.tpz", "", "", false, false},
+		{"mono_export_templates.tpz", "", "", false, false},
+		{"web_editor.zip", "", "", false, false},
+		{"android_editor.apk", "", "", false, false},
+		{"linux.x86_64", "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		goos, arch, dotnet, ok := parseAssetPlatform(tt.name)
+		if goos != tt.goos || arch != tt.arch || dotnet != tt.dotnet || ok != tt.ok {
+			t.Errorf("parseAssetPlatform(%q) = %q, %q, %v, %v, want %q, %q, %v, %v",
+				tt.name, goos, arch, dotnet, ok, tt.goos, tt.arch, tt.dotnet, tt.ok)
+		}
+	}
+}
+
+func TestVersionsFromRelease(t *testing.T) {
+	versions := versionsFromRelease(release("4.4-rc2",
+		"linux.x86_64.zip", "mono_linux_arm64.zip", "export_templates.tpz", "macos.universal.zip"))
+
+	if len(versions) != 3 {
+		t.Fatalf("got %d versions, want 3: %+v", len(versions), versions)
+	}
+
+	mono := versions[1]
+	if mono.DisplayName != "4.4.0-rc2 (Mono)" || mono.Version != "4.4.0-rc2" || mono.Tag != "4.4-rc2" ||
+		!mono.DotNet || mono.OS != "linux" || mono.Arch != "arm64" {
+		t.Errorf("unexpected mono version: %+v", mono)
+	}
+	if mono.URL != "https://example.com/Godot_v4.4-rc2_mono_linux_arm64.zip" {
+		t.Errorf("URL = %q", mono.URL)
+	}
+	if mono.SumsURL != "https://example.com/4.4-rc2/"+SumsFileName {
+		t.Errorf("SumsURL = %q", mono.SumsURL)
+	}
+	if mono.Stable() {
+		t.Error("rc release reported as stable")
+	}
+}
+
+func TestVersionFromTag(t *testing.T) {
+	tests := map[string]string{
+		"4.3-stable":   "4.3.0",
+		"4.2.2-stable": "4.2.2",
+		"4.4-rc2":      "4.4.0-rc2",
+		"4.5-dev1":     "4.5.0-dev1",
+		"invalid":      "",
+	}
+	for tag, want := range tests {
+		if got := versionFromTag(tag); got != want {
+			t.Errorf("versionFromTag(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestFetchManifestPagination(t *testing.T) {
+	var releases []githubRelease
+	for i := 0; i < releasesPerPage+5; i++ {
+		releases = append(releases, release(fmt.Sprintf("4.%d-stable", i), "linux.x86_64.zip"))
+	}
+	requests := releasesServer(t, releases)
+
+	versions, err := FetchManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != len(releases) {
+		t.Errorf("got %d versions, want %d", len(versions), len(releases))
+	}
+	if *requests != 2 {
+		t.Errorf("fetched %d pages, want 2", *requests)
+	}
+	if last := versions[len(versions)-1]; last.Tag != releases[len(releases)-1].TagName {
+		t.Errorf("last version is %s, want %s", last.Tag, releases[len(releases)-1].TagName)
+	}
+}
+
+func TestRefreshManifestCache(t *testing.T) {
+	setHome(t)
+	requests := releasesServer(t, []githubRelease{release("4.3-stable", "linux.x86_64.zip", "win64.exe.zip")})
+
+	if err := RefreshManifest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(VersionManifest) != 2 || *requests != 1 {
+		t.Fatalf("got %d versions after %d requests", len(VersionManifest), *requests)
+	}
+
+	// A fresh cache is used without asking the API
+	VersionManifest = nil
+	if err := RefreshManifest(); err != nil {
+		t.Fatal(err)
+	}
+	if len(VersionManifest) != 2 || *requests != 1 {
+		t.Fatalf("got %d versions after %d requests, want the cached list", len(VersionManifest), *requests)
+	}
+
+	// A stale cache is fetched again
+	cache, err := readManifestCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.FetchedAt = time.Now().Add(-ManifestCacheTTL - time.Minute)
+	data, _ := json.Marshal(cache)
+	if err := os.WriteFile(manifestCachePath(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RefreshManifest(); err != nil {
+		t.Fatal(err)
+	}
+	if *requests != 2 {
+		t.Errorf("stale cache not refetched, %d requests", *requests)
+	}
+}
+
+func TestRefreshManifestFallback(t *testing.T) {
+	setHome(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	useReleasesAPI(t, server.URL)
+
+	VersionManifest = nil
+	if err := RefreshManifest(); err == nil {
+		t.Error("expected an error describing the fallback")
+	}
+	if len(VersionManifest) != len(builtinManifest) || VersionManifest[0].URL != builtinManifest[0].URL {
+		t.Errorf("did not fall back to the built-in list: %+v", VersionManifest)
+	}
+}
+
+func TestRefreshManifestStaleCacheFallback(t *testing.T) {
+	setHome(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	useReleasesAPI(t, server.URL)
+
+	cached := []GodotVersion{{DisplayName: "4.2.0 (Standard)", Version: "4.2.0", OS: "linux", Arch: "amd64"}}
+	data, _ := json.Marshal(manifestCache{FetchedAt: time.Now().Add(-48 * time.Hour), Versions: cached})
+	if err := os.MkdirAll(GetInstallPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(manifestCachePath(), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RefreshManifest(); err == nil {
+		t.Error("expected an error describing the fallback")
+	}
+	if len(VersionManifest) != 1 || VersionManifest[0].Version != "4.2.0" {
+		t.Errorf("did not fall back to the stale cache: %+v", VersionManifest)
+	}
+}
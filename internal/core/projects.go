@@ -0,0 +1,120 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ProjectsFile lists every project linked to an engine in the store, so that
+// engines still in use are kept when pruning.
+const ProjectsFile = "projects.json"
+
+func projectsPath() string {
+	return filepath.Join(GetHomePath(), ProjectsFile)
+}
+
+// KnownProjects returns the directories of every project that was linked to
+// an engine in the store.
+func KnownProjects() ([]string, error) {
+	data, err := os.ReadFile(projectsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func saveKnownProjects(projects []string) error {
+	sort.Strings(projects)
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(GetHomePath(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(projectsPath(), data, 0644)
+}
+
+// RegisterProject adds the project directory to the known projects.
+func RegisterProject(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	projects, err := KnownProjects()
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p == dir {
+			return nil
+		}
+	}
+
+	return saveKnownProjects(append(projects, dir))
+}
+
+// linkedEngineDir returns the store directory of the engine the project is
+// linked to.
+func linkedEngineDir(project string) (string, error) {
+	name, err := readProjectLink(project)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(GetInstallPath(), name), nil
+}
+
+// EngineReferences maps the store directory of every engine in use to the
+// projects using it. Projects that were deleted or no longer have an engine
+// link are skipped, but stay known until ForgetStaleProjects.
+func EngineReferences() (map[string][]string, error) {
+	projects, err := KnownProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	references := make(map[string][]string)
+	for _, project := range projects {
+		if dir, err := linkedEngineDir(project); err == nil {
+			references[dir] = append(references[dir], project)
+		}
+	}
+	return references, nil
+}
+
+// ForgetStaleProjects removes the projects that were deleted or no longer
+// have an engine link from the known projects, and returns them.
+func ForgetStaleProjects() ([]string, error) {
+	projects, err := KnownProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining, stale []string
+	for _, project := range projects {
+		if _, err := linkedEngineDir(project); err != nil {
+			stale = append(stale, project)
+		} else {
+			remaining = append(remaining, project)
+		}
+	}
+
+	if len(stale) > 0 {
+		if err := saveKnownProjects(remaining); err != nil {
+			return nil, err
+		}
+	}
+	return stale, nil
+}
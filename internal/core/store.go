@@ -0,0 +1,287 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DependenciesDir is the per-project directory holding the engine link.
+	DependenciesDir = "dependencies"
+
+	// ProjectEngineFile records which engine in the store a project uses.
+	ProjectEngineFile = "engine.json"
+
+	// EngineInfoFile is written into an engine directory in the store once the
+	// engine is completely installed.
+	EngineInfoFile = ".gdcli-engine.json"
+)
+
+// ProjectEngine is the engine in the store a project is linked to.
+type ProjectEngine struct {
+	Version GodotVersion // As recorded by the install, with its layout
+	Path    string       // Directory of the engine in the store
+}
+
+// projectLink is the content of a project's engine.json. Only the engine's
+// directory in the store is recorded, the rest is read from the store, so a
+// reinstalled engine is picked up by every project linked to it.
+type projectLink struct {
+	StoreName string `json:"store_name"`
+}
+
+// readProjectLink returns the store name of the engine the project in dir is
+// linked to.
+func readProjectLink(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, DependenciesDir, ProjectEngineFile))
+	if err != nil {
+		return "", err
+	}
+
+	var link projectLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return "", fmt.Errorf("failed to read engine link: %v", err)
+	}
+	if link.StoreName == "" {
+		return "", fmt.Errorf("engine link in %s names no engine", dir)
+	}
+	return link.StoreName, nil
+}
+
+// StoreName is the name of the directory holding the version in the store,
+// e.g. "4.3-stable_mono_amd64" or "4.3-stable_standard_arm64", or
+// "custom_<name>" for custom engines.
+func (v GodotVersion) StoreName() string {
+	if v.Custom != "" {
+		return "custom_" + v.Custom
+	}
+
+	name := v.Tag
+	if name == "" {
+		name = v.Version
+	}
+
+	if v.DotNet {
+		name += "_mono"
+	} else {
+		name += "_standard"
+	}
+
+	return name + "_" + v.Arch
+}
+
+// EngineDir returns the directory the version is installed to in the store.
+func EngineDir(version GodotVersion) string {
+	return filepath.Join(GetInstallPath(), version.StoreName())
+}
+
+// IsEngineInstalled reports whether the version is completely installed in
+// the store, from the same download or path. A custom engine registered again
+// with another source needs to be installed again.
+func IsEngineInstalled(version GodotVersion) bool {
+	installed, err := installedEngine(version)
+	if err != nil {
+		return false
+	}
+	return installed.URL == version.URL && installed.Path == version.Path
+}
+
+// installedEngine reads the version as recorded by its install in the store,
+// with the layout of the engine.
+func installedEngine(version GodotVersion) (GodotVersion, error) {
+	return readEngineInfo(EngineDir(version))
+}
+
+// readEngineInfo reads the version installed in the engine directory.
+func readEngineInfo(engineDir string) (GodotVersion, error) {
+	data, err := os.ReadFile(filepath.Join(engineDir, EngineInfoFile))
+	if err != nil {
+		return GodotVersion{}, err
+	}
+
+	var installed GodotVersion
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return GodotVersion{}, err
+	}
+	return installed, nil
+}
+
+// writeEngineInfo records the version as completely installed in engineDir.
+func writeEngineInfo(engineDir string, version GodotVersion) error {
+	data, err := json.MarshalIndent(version, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(engineDir, EngineInfoFile), data, 0644)
+}
+
+// LinkProject points the project in the current directory at the version in
+// the store.
+func LinkProject(version GodotVersion) error {
+	if err := os.MkdirAll(DependenciesDir, 0755); err != nil {
+		return err
+	}
+
+	gdIgnorePath := filepath.Join(DependenciesDir, ".gdignore")
+	if _, err := os.Create(gdIgnorePath); err != nil {
+		return err
+	}
+
+	if _, err := installedEngine(version); err != nil {
+		return fmt.Errorf("failed to read installed engine: %v", err)
+	}
+
+	data, err := json.MarshalIndent(projectLink{StoreName: version.StoreName()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Written next to the link and renamed, so an interrupted write keeps the
+	// previous link
+	linkPath := filepath.Join(DependenciesDir, ProjectEngineFile)
+	if err := os.WriteFile(linkPath+".tmp", data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(linkPath+".tmp", linkPath); err != nil {
+		os.Remove(linkPath + ".tmp")
+		return err
+	}
+
+	return RegisterProject(".")
+}
+
+// LoadProjectEngine reads the engine link of the project in the current
+// directory and the linked engine's install from the store.
+func LoadProjectEngine() (*ProjectEngine, error) {
+	name, err := readProjectLink(".")
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(GetInstallPath(), name)
+	version, err := readEngineInfo(dir)
+	if err != nil {
+		return nil, fmt.Errorf("engine %s is missing from the store", name)
+	}
+	return &ProjectEngine{Version: version, Path: dir}, nil
+}
+
+// ProjectEnginePath returns the path of the Godot executable used by the
+// project in the current directory. Projects installed before the store
+// existed keep their engine in the dependencies directory.
+func ProjectEnginePath() (string, error) {
+	engine, err := LoadProjectEngine()
+	if err == nil {
+		return engine.EnginePath(), nil
+	}
+	return legacyProjectPath(LegacyExecutable, err)
+}
+
+// ProjectConsolePath returns the path of the Godot executable to run the
+// project in the current directory from a terminal with, e.g. for exports.
+func ProjectConsolePath() (string, error) {
+	engine, err := LoadProjectEngine()
+	if err == nil {
+		return engine.ConsolePath(), nil
+	}
+
+	if path, legacyErr := legacyProjectPath(legacyConsole, err); legacyErr == nil {
+		return path, nil
+	}
+	return legacyProjectPath(LegacyExecutable, err)
+}
+
+func legacyProjectPath(name string, linkErr error) (string, error) {
+	legacyPath := filepath.Join(DependenciesDir, name)
+	if _, err := os.Stat(legacyPath); err == nil {
+		return legacyPath, nil
+	}
+
+	if os.IsNotExist(linkErr) {
+		return "", fmt.Errorf("no engine installed for this project")
+	}
+	return "", linkErr
+}
+
+// InstalledEngines returns every version completely installed in the store.
+func InstalledEngines() ([]GodotVersion, error) {
+	entries, err := os.ReadDir(GetInstallPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []GodotVersion
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		version, err := readEngineInfo(filepath.Join(GetInstallPath(), entry.Name()))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// FindInstalledEngine returns the installed version matching the identifier,
+// which is a display name, store directory name or part of a display name.
+func FindInstalledEngine(identifier string) (GodotVersion, error) {
+	versions, err := InstalledEngines()
+	if err != nil {
+		return GodotVersion{}, err
+	}
+
+	for _, v := range versions {
+		if strings.EqualFold(v.DisplayName, identifier) || v.StoreName() == identifier {
+			return v, nil
+		}
+	}
+
+	var matches []GodotVersion
+	for _, v := range versions {
+		if strings.Contains(strings.ToLower(v.DisplayName), strings.ToLower(identifier)) {
+			matches = append(matches, v)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return GodotVersion{}, fmt.Errorf("no installed versions found matching '%s'", identifier)
+	default:
+		var options []string
+		for _, m := range matches {
+			options = append(options, m.DisplayName)
+		}
+		return GodotVersion{}, fmt.Errorf("multiple matches found:\n%s", strings.Join(options, "\n"))
+	}
+}
+
+// DirSize returns the total size of the files below path.
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
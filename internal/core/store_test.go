@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// inProject runs the test in a new project directory.
+func inProject(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// fakeInstall records the version as installed in the store with the layout.
+func fakeInstall(t *testing.T, version GodotVersion, layout EngineLayout) {
+	t.Helper()
+	dir := EngineDir(version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	version.Layout = &layout
+	if err := writeEngineInfo(dir, version); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLinkedProjectFollowsReinstall(t *testing.T) {
+	setHome(t)
+	inProject(t)
+
+	version := GodotVersion{DisplayName: "mybuild", Custom: "mybuild", OS: "linux", Arch: "amd64", Path: "/src/godot"}
+	fakeInstall(t, version, EngineLayout{Executable: "godot.linuxbsd.editor.x86_64"})
+	if err := LinkProject(version); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := LoadProjectEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(EngineDir(version), "godot.linuxbsd.editor.x86_64"); engine.EnginePath() != want {
+		t.Errorf("EnginePath() = %q, want %q", engine.EnginePath(), want)
+	}
+
+	// A rebuild with another executable name replaces the install
+	fakeInstall(t, version, EngineLayout{Executable: "godot.linuxbsd.editor.dev.x86_64", SharpDir: "GodotSharp"})
+
+	engine, err = LoadProjectEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(EngineDir(version), "godot.linuxbsd.editor.dev.x86_64"); engine.EnginePath() != want {
+		t.Errorf("EnginePath() after reinstall = %q, want %q", engine.EnginePath(), want)
+	}
+	if want := filepath.Join(EngineDir(version), "GodotSharp"); engine.SharpPath() != want {
+		t.Errorf("SharpPath() after reinstall = %q, want %q", engine.SharpPath(), want)
+	}
+}
+
+func TestLoadProjectEngineMissing(t *testing.T) {
+	setHome(t)
+	inProject(t)
+
+	if _, err := LoadProjectEngine(); !os.IsNotExist(err) {
+		t.Errorf("project without link: %v", err)
+	}
+
+	version := GodotVersion{Custom: "gone", OS: "linux"}
+	fakeInstall(t, version, EngineLayout{Executable: "godot"})
+	if err := LinkProject(version); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(EngineDir(version)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadProjectEngine(); err == nil {
+		t.Error("link to an uninstalled engine was loaded")
+	}
+	if _, err := ProjectEnginePath(); err == nil {
+		t.Error("ProjectEnginePath() of an uninstalled engine succeeded")
+	}
+}
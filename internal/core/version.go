@@ -1,76 +1,170 @@
 package core
 
 import (
-	"archive/zip"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
-	"time"
+
+	"github.com/IgorBayerl/gdcli/internal/archive"
+	"github.com/IgorBayerl/gdcli/internal/semver"
 )
 
 type GodotVersion struct {
-	DisplayName string // User-friendly name for selection
-	Version     string // Base version number
-	DotNet      bool   // Whether this is a Mono/.NET version
-	URL         string // Download URL
-	OS          string // Operating System (Architecture assumed as x64)
+	DisplayName string `json:"display_name"`       // User-friendly name for selection
+	Version     string `json:"version"`            // Base version number
+	Tag         string `json:"tag"`                // Release tag on godot-builds, e.g. "4.3-stable"
+	DotNet      bool   `json:"dotnet"`             // Whether this is a Mono/.NET version
+	URL         string `json:"url"`                // Download URL
+	SHA512      string `json:"sha512,omitempty"`   // Expected checksum of the download, if known
+	SumsURL     string `json:"sums_url,omitempty"` // URL of the release's SHA512-SUMS.txt
+	OS          string `json:"os"`                 // Operating system, as GOOS
+	Arch        string `json:"arch,omitempty"`     // Architecture, as GOARCH or "universal" for macOS
+	Custom      string `json:"custom,omitempty"`   // Name of an engine registered with 'gdcli engine add'
+	Path        string `json:"path,omitempty"`     // Local engine directory or executable of a custom engine
+	Commit      string `json:"commit,omitempty"`   // Source commit of an engine built with 'gdcli engine build'
+
+	Layout *EngineLayout `json:"layout,omitempty"` // Executables of the engine, recorded when it is installed
+}
+
+// ConfigVersion is the engine_version gdproj.json selects the version with,
+// the name for custom engines.
+func (v GodotVersion) ConfigVersion() string {
+	if v.Custom != "" {
+		return v.Custom
+	}
+	return v.Version
+}
+
+// Stable reports whether the version comes from a stable release rather than
+// a dev, beta or rc snapshot.
+func (v GodotVersion) Stable() bool {
+	return v.Tag == "" || strings.HasSuffix(v.Tag, "-stable")
 }
 
-var VersionManifest = []GodotVersion{
+// VersionManifest is the list of versions known to gdcli. It starts out as the
+// built-in list and is replaced by RefreshManifest with the releases published
+// on godot-builds.
+var VersionManifest = builtinManifest
+
+// builtinManifest is used when the release list can neither be fetched nor
+// read from the cache, e.g. when offline on first use.
+var builtinManifest = []GodotVersion{
 	{
 		DisplayName: "4.3.0 (Standard)",
 		Version:     "4.3.0",
+		Tag:         "4.3-stable",
 		DotNet:      false,
 		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/Godot_v4.3-stable_win64.exe.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/SHA512-SUMS.txt",
 		OS:          "windows",
+		Arch:        "amd64",
 	},
 	{
 		DisplayName: "4.3.0 (Mono)",
 		Version:     "4.3.0",
+		Tag:         "4.3-stable",
 		DotNet:      true,
 		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/Godot_v4.3-stable_mono_win64.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/SHA512-SUMS.txt",
 		OS:          "windows",
+		Arch:        "amd64",
 	},
 	{
 		DisplayName: "4.3.0 (Standard)",
 		Version:     "4.3.0",
+		Tag:         "4.3-stable",
 		DotNet:      false,
 		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/Godot_v4.3-stable_linux.x86_64.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/SHA512-SUMS.txt",
 		OS:          "linux",
+		Arch:        "amd64",
 	},
 	{
 		DisplayName: "4.3.0 (Mono)",
 		Version:     "4.3.0",
+		Tag:         "4.3-stable",
 		DotNet:      true,
 		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/Godot_v4.3-stable_mono_linux_x86_64.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/SHA512-SUMS.txt",
 		OS:          "linux",
+		Arch:        "amd64",
+	},
+	{
+		DisplayName: "4.3.0 (Standard)",
+		Version:     "4.3.0",
+		Tag:         "4.3-stable",
+		DotNet:      false,
+		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/Godot_v4.3-stable_macos.universal.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/SHA512-SUMS.txt",
+		OS:          "darwin",
+		Arch:        UniversalArch,
+	},
+	{
+		DisplayName: "4.3.0 (Mono)",
+		Version:     "4.3.0",
+		Tag:         "4.3-stable",
+		DotNet:      true,
+		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/Godot_v4.3-stable_mono_macos.universal.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.3-stable/SHA512-SUMS.txt",
+		OS:          "darwin",
+		Arch:        UniversalArch,
 	},
 	{
 		DisplayName: "4.4.0 (Standard)",
 		Version:     "4.4.0",
+		Tag:         "4.4-stable",
 		DotNet:      false,
 		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.4-stable/Godot_v4.4-stable_linux.x86_64.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.4-stable/SHA512-SUMS.txt",
 		OS:          "linux",
+		Arch:        "amd64",
+	},
+	{
+		DisplayName: "4.4.0 (Standard)",
+		Version:     "4.4.0",
+		Tag:         "4.4-stable",
+		DotNet:      false,
+		URL:         "https://github.com/godotengine/godot-builds/releases/download/4.4-stable/Godot_v4.4-stable_macos.universal.zip",
+		SumsURL:     "https://github.com/godotengine/godot-builds/releases/download/4.4-stable/SHA512-SUMS.txt",
+		OS:          "darwin",
+		Arch:        UniversalArch,
 	},
-	// Add more versions as needed
 }
 
-func GetVersionByIdentifier(identifier string) (GodotVersion, error) {
+// GetVersionByIdentifier returns the version for the platform named by the
+// identifier, a display name such as "4.3.0 (Mono)", a version number such as
+// "4.3.0" or a part of a display name. A version number picks the Standard
+// variant unless dotnet asks for the Mono one.
+func GetVersionByIdentifier(identifier string, dotnet bool) (GodotVersion, error) {
+	if v, ok := FindCustomEngine(identifier); ok {
+		return v, nil
+	}
+
 	var matches []GodotVersion
 
-	currentOS := runtime.GOOS
 	for _, v := range VersionManifest {
-		if v.OS == currentOS && strings.EqualFold(v.DisplayName, identifier) || v.Version == identifier {
+		if v.ForPlatform() && strings.EqualFold(v.DisplayName, identifier) {
 			return v, nil
 		}
 	}
 
 	for _, v := range VersionManifest {
-		if v.OS == currentOS && strings.Contains(strings.ToLower(v.DisplayName), strings.ToLower(identifier)) {
+		if v.ForPlatform() && v.Version == identifier {
+			if v.DotNet == dotnet {
+				return v, nil
+			}
+			matches = append(matches, v)
+		}
+	}
+	if len(matches) > 0 {
+		// Only the other variant is published for this version
+		return matches[0], nil
+	}
+
+	for _, v := range VersionManifest {
+		if v.ForPlatform() && strings.Contains(strings.ToLower(v.DisplayName), strings.ToLower(identifier)) {
 			matches = append(matches, v)
 		}
 	}
@@ -89,284 +183,292 @@ func GetVersionByIdentifier(identifier string) (GodotVersion, error) {
 	}
 }
 
-func InstallGodotVersion(version GodotVersion) error {
-	if version.URL == "" {
-		return fmt.Errorf("no URL found for version %s", version.DisplayName)
+// ResolveVersion returns the newest version for the platform that satisfies
+// the constraint, e.g. "4.3.0", "~4.3", ">=4.2 <4.5" or "4.x", or the custom
+// engine with that name.
+func ResolveVersion(constraint string, dotnet bool) (GodotVersion, error) {
+	if v, ok := FindCustomEngine(constraint); ok {
+		return v, nil
 	}
 
-	if err := os.MkdirAll("dependencies", 0755); err != nil {
-		return err
+	c, err := semver.ParseConstraint(constraint)
+	if err != nil {
+		if ValidateCustomName(constraint) == nil {
+			return GodotVersion{}, CustomEngineMissingError(constraint)
+		}
+		return GodotVersion{}, err
 	}
 
-	gdIgnorePath := filepath.Join("dependencies", ".gdignore")
-	if _, err := os.Create(gdIgnorePath); err != nil {
-		return err
-	}
+	var best GodotVersion
+	var bestVersion semver.Version
+	found := false
 
-	zipName := filepath.Base(version.URL)
-	zipPath := filepath.Join("dependencies", zipName)
+	for _, v := range VersionManifest {
+		if !v.ForPlatform() || v.DotNet != dotnet {
+			continue
+		}
 
-	fmt.Printf("Downloading %s...\n", zipName)
-	if err := downloadFile(zipPath, version.URL); err != nil {
-		return err
-	}
+		parsed, err := semver.Parse(v.Version)
+		if err != nil || !c.Check(parsed) {
+			continue
+		}
 
-	tempDir := filepath.Join("dependencies", "temp_extract")
-	defer os.RemoveAll(tempDir)
+		if !found || semver.Compare(parsed, bestVersion) > 0 {
+			best, bestVersion, found = v, parsed, true
+		}
+	}
 
-	fmt.Printf("Extracting %s...\n", zipName)
-	if err := extractZip(zipPath, tempDir); err != nil {
-		return err
+	if !found {
+		return GodotVersion{}, fmt.Errorf("no versions found matching '%s'", constraint)
 	}
+	return best, nil
+}
 
-	exeDir, _, err := findExePath(tempDir)
-	if err != nil {
-		return fmt.Errorf("error locating executables: %v", err)
+// InstallGodotVersion installs the version into the global engine store,
+// unless it is already there, and links the current project to it.
+func InstallGodotVersion(version GodotVersion) error {
+	if err := InstallEngine(version); err != nil {
+		return err
 	}
+	return LinkProject(version)
+}
 
-	if err := moveFilesFromSubdir(exeDir, "dependencies"); err != nil {
-		return fmt.Errorf("error moving files: %v", err)
+// InstallEngine installs the version into the global engine store, unless it
+// is already there.
+func InstallEngine(version GodotVersion) error {
+	if IsEngineInstalled(version) {
+		fmt.Printf("Godot %s is already installed in %s\n", version.DisplayName, EngineDir(version))
+		return nil
 	}
+	return ReinstallEngine(version)
+}
 
-	if err := os.Remove(zipPath); err != nil {
-		return fmt.Errorf("failed to remove zip: %v", err)
+// ReinstallEngine installs the version into the global engine store,
+// replacing an installed engine of the same name. The engine is staged in a
+// temporary directory and only swapped in once it is complete, so a failed or
+// interrupted install leaves the installed engine untouched.
+func ReinstallEngine(version GodotVersion) error {
+	if version.URL == "" && version.Path == "" {
+		return fmt.Errorf("no URL found for version %s", version.DisplayName)
 	}
 
-	if err := renameExecutables("dependencies"); err != nil {
+	if err := os.MkdirAll(StagingDir(), 0755); err != nil {
 		return err
 	}
-
-	fmt.Printf("Successfully installed Godot %s\n", version.DisplayName)
-	return nil
-}
-
-func getGodotExe(inDir string) (string, string, error) {
-	var exeName string
-	var consoleExeName string
-
-	entries, err := os.ReadDir(inDir)
+	stageDir, err := os.MkdirTemp(StagingDir(), version.StoreName()+"-")
 	if err != nil {
-		return "", "", err
+		return err
 	}
+	defer removeOnInterrupt(stageDir)()
+	defer os.RemoveAll(stageDir)
 
-	for _, info := range entries {
-		switch runtime.GOOS {
-		case "linux":
-			// TODO handle other architectures?
-			if strings.HasPrefix(info.Name(), "Godot_") && strings.EqualFold(filepath.Ext(info.Name()), ".x86_64") {
-				exeName = info.Name()
-				break
-			}
-		case "windows":
-			name := info.Name()
-			lowerName := strings.ToLower(name)
-			if strings.Contains(lowerName, "_console") && strings.HasSuffix(lowerName, ".exe") {
-				consoleExeName = name
-			} else if strings.HasSuffix(lowerName, ".exe") && !strings.Contains(lowerName, "_console") {
-				exeName = name
-			}
-		}
+	stagedEngine := filepath.Join(stageDir, "engine")
+	if err := os.MkdirAll(stagedEngine, 0755); err != nil {
+		return err
 	}
 
-	return exeName, consoleExeName, err
-}
-
-var errFound = fmt.Errorf("found")
-
-func findExePath(searchDir string) (string, string, error) {
-    var foundExeDir, foundExe string
-
-    err := filepath.Walk(searchDir, func(path string, info os.FileInfo, err error) error {
-        if err != nil {
-            return err
-        }
-        if !info.IsDir() {
-            return nil
-        }
-
-        exe, _, err := getGodotExe(path)
-        if err != nil {
-            return err
-        }
-        if exe != "" {
-            foundExeDir = path
-            foundExe = exe
-            // Return a sentinel error to stop the walk.
-            return errFound
-        }
-        return nil
-    })
-
-    // If we broke out because we found the exe, ignore the sentinel error.
-    if err != nil && err != errFound {
-        return "", "", err
-    }
-    if foundExeDir == "" || foundExe == "" {
-        return "", "", fmt.Errorf("no executables found in %s", searchDir)
-    }
-    return foundExeDir, foundExe, nil
-}
-
-
-func moveFilesFromSubdir(src, dest string) error {
-	entries, err := os.ReadDir(src)
+	var layout EngineLayout
+	if version.Path != "" {
+		layout, err = copyLocalEngine(version, stagedEngine)
+	} else {
+		layout, err = downloadEngine(version, stageDir, stagedEngine)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read source directory: %v", err)
+		return err
 	}
 
-	if err := os.MkdirAll(dest, 0755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %v", err)
+	version.Layout = &layout
+	if err := writeEngineInfo(stagedEngine, version); err != nil {
+		return fmt.Errorf("failed to record installed engine: %v", err)
 	}
 
-	for _, entry := range entries {
-		srcPath := filepath.Join(src, entry.Name())
-		destPath := filepath.Join(dest, entry.Name())
-
-		if err := os.Rename(srcPath, destPath); err != nil {
-			return fmt.Errorf("failed to move %s: %v", entry.Name(), err)
-		}
+	if err := uninterrupted(func() error {
+		return swapDir(stagedEngine, EngineDir(version), filepath.Join(stageDir, "previous"))
+	}); err != nil {
+		return fmt.Errorf("failed to install engine: %v", err)
 	}
 
+	fmt.Printf("Successfully installed Godot %s\n", version.DisplayName)
 	return nil
 }
 
-func renameExecutables(targetDir string) error {
-	newMainPath := filepath.Join(targetDir, "godot.exe")
-	newConsolePath := filepath.Join(targetDir, "godot_console.exe")
+// downloadEngine downloads and extracts the version's engine into engineDir,
+// using stageDir for the extracted archive.
+func downloadEngine(version GodotVersion, stageDir, engineDir string) (EngineLayout, error) {
+	if err := os.MkdirAll(DownloadsDir(), 0755); err != nil {
+		return EngineLayout{}, err
+	}
 
-	if _, err := os.Stat(newMainPath); err == nil {
-		if err := os.Remove(newMainPath); err != nil {
-			return fmt.Errorf("failed to remove existing godot.exe: %v", err)
-		}
+	zipName := filepath.Base(version.URL)
+	zipPath := filepath.Join(DownloadsDir(), zipName)
+
+	checksum, err := ExpectedChecksum(version)
+	if err != nil {
+		return EngineLayout{}, err
 	}
-	if _, err := os.Stat(newConsolePath); err == nil {
-		if err := os.Remove(newConsolePath); err != nil {
-			return fmt.Errorf("failed to remove existing godot_console.exe: %v", err)
-		}
+	if checksum == "" {
+		fmt.Printf("Warning: no checksum published for %s, skipping verification\n", zipName)
 	}
 
-	// Wait for the filesystem to settle.
-	time.Sleep(1 * time.Second)
+	fmt.Printf("Downloading %s...\n", zipName)
+	if err := DownloadFile(zipPath, version.URL, checksum); err != nil {
+		return EngineLayout{}, err
+	}
 
-	var mainExe, consoleExe string
+	tempDir := filepath.Join(stageDir, "extract")
 
-	// Retry mechanism in case of delays in file availability.
-	retryCount := 3
-	for i := 0; i < retryCount; i++ {
-		// Search for the Godot executables
-		var err error
-		mainExe, consoleExe, err = getGodotExe(targetDir)
+	fmt.Printf("Extracting %s...\n", zipName)
+	if err := archive.ExtractZip(zipPath, tempDir); err != nil {
+		return EngineLayout{}, err
+	}
 
-		if err != nil {
-			return fmt.Errorf("failed to read directory: %v", err)
-		}
+	exeDir, err := findEngineDir(tempDir, version)
+	if err != nil {
+		return EngineLayout{}, fmt.Errorf("error locating executables: %v", err)
+	}
 
-		// If at least the main exe is found, break out of the loop.
-		if mainExe != "" {
-			break
-		}
+	if err := moveFilesFromSubdir(exeDir, engineDir); err != nil {
+		return EngineLayout{}, fmt.Errorf("error moving files: %v", err)
+	}
 
-		fmt.Println("Retrying file detection...")
-		time.Sleep(1 * time.Second)
+	layout, err := installedLayout(engineDir, version)
+	if err != nil {
+		return EngineLayout{}, err
 	}
 
-	// Copy main executable.
-	if mainExe != "" {
-		mainPath := filepath.Join(targetDir, mainExe)
-		if err := copyFile(mainPath, newMainPath); err != nil {
-			return fmt.Errorf("failed to copy main executable: %v", err)
-		}
-		if err := os.Remove(mainPath); err != nil {
-			fmt.Printf("Warning: failed to remove original main executable %s: %v\n", mainExe, err)
-		}
-		fmt.Printf("Copied %s -> godot.exe\n", mainExe)
+	if err := os.Remove(zipPath); err != nil {
+		return EngineLayout{}, fmt.Errorf("failed to remove zip: %v", err)
+	}
+	return layout, nil
+}
 
-		// Probably the same with "darwin" AKA MacOS
-		if runtime.GOOS == "linux" {
-			fmt.Printf("Attempting to set executable permission...")
-			os.Chmod(newMainPath, os.FileMode(0755))
+// swapDir moves src to dest. An existing dest is moved to backup first and
+// moved back if src cannot be moved into place.
+func swapDir(src, dest, backup string) error {
+	if _, err := os.Stat(dest); err == nil {
+		if err := os.Rename(dest, backup); err != nil {
+			return err
 		}
-	} else {
-		return fmt.Errorf("main executable not found after extraction")
+	} else if !os.IsNotExist(err) {
+		return err
 	}
 
-	// Copy console executable if found.
-	if consoleExe != "" {
-		consolePath := filepath.Join(targetDir, consoleExe)
-		if err := copyFile(consolePath, newConsolePath); err != nil {
-			return fmt.Errorf("failed to copy console executable: %v", err)
-		}
-		if err := os.Remove(consolePath); err != nil {
-			fmt.Printf("Warning: failed to remove original console executable %s: %v\n", consoleExe, err)
+	if err := os.Rename(src, dest); err != nil {
+		if _, statErr := os.Stat(backup); statErr == nil {
+			if restoreErr := os.Rename(backup, dest); restoreErr != nil {
+				return fmt.Errorf("%v, and restoring %s failed: %v", err, dest, restoreErr)
+			}
 		}
-		fmt.Printf("Copied %s -> godot_console.exe\n", consoleExe)
-	} else {
-		fmt.Println("Warning: Console executable not found after extraction")
+		return err
 	}
-
 	return nil
 }
 
-func downloadFile(path string, url string) error {
-	resp, err := http.Get(url)
+// copyLocalEngine copies the engine of a custom version registered with a
+// path into engineDir. An executable is copied under its own name, a
+// directory is searched for the executables like an extracted download and a
+// macOS .app bundle is copied as is.
+func copyLocalEngine(version GodotVersion, engineDir string) (EngineLayout, error) {
+	path := version.Path
+	info, err := os.Stat(path)
 	if err != nil {
-		return err
+		return EngineLayout{}, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
+	fmt.Printf("Copying %s...\n", path)
+	if !info.IsDir() {
+		layout := EngineLayout{Executable: filepath.Base(path)}
+		mainPath := filepath.Join(engineDir, layout.Executable)
+		if err := copyFile(path, mainPath); err != nil {
+			return EngineLayout{}, fmt.Errorf("failed to copy executable: %v", err)
+		}
+
+		// Mono builds need their assemblies next to the executable
+		sharpPath := filepath.Join(filepath.Dir(path), sharpDir)
+		if _, err := os.Stat(sharpPath); err == nil {
+			if err := CopyDir(sharpPath, filepath.Join(engineDir, sharpDir)); err != nil {
+				return EngineLayout{}, fmt.Errorf("failed to copy GodotSharp: %v", err)
+			}
+			layout.SharpDir = sharpDir
+		}
+		return layout, os.Chmod(mainPath, 0755)
 	}
 
-	out, err := os.Create(path)
-	if err != nil {
-		return err
+	// A macOS bundle is kept as a whole
+	if strings.EqualFold(filepath.Ext(path), ".app") {
+		if err := CopyDir(path, filepath.Join(engineDir, filepath.Base(path))); err != nil {
+			return EngineLayout{}, fmt.Errorf("failed to copy engine: %v", err)
+		}
+		return installedLayout(engineDir, version)
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
-}
+	tempDir := filepath.Join(engineDir, "temp_extract")
+	defer os.RemoveAll(tempDir)
+	if err := CopyDir(path, tempDir); err != nil {
+		return EngineLayout{}, fmt.Errorf("failed to copy engine: %v", err)
+	}
 
-func extractZip(src, dest string) error {
-	r, err := zip.OpenReader(src)
+	exeDir, err := findEngineDir(tempDir, version)
 	if err != nil {
-		return err
+		return EngineLayout{}, fmt.Errorf("error locating executables: %v", err)
 	}
-	defer r.Close()
-
-	for _, f := range r.File {
-		// Skip directories
-		if f.FileInfo().IsDir() {
-			continue
-		}
-
-		// Create file path
-		path := filepath.Join(dest, f.Name)
+	if err := moveFilesFromSubdir(exeDir, engineDir); err != nil {
+		return EngineLayout{}, fmt.Errorf("error moving files: %v", err)
+	}
+	return installedLayout(engineDir, version)
+}
 
-		// Create parent directories
-		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+// CopyDir copies the directory tree at src to dest, keeping file modes and
+// symlinks.
+func CopyDir(src, dest string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
 			return err
 		}
 
-		// Extract file
-		out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+		rel, err := filepath.Rel(src, path)
 		if err != nil {
 			return err
 		}
-		defer out.Close()
+		target := filepath.Join(dest, rel)
 
-		rc, err := f.Open()
-		if err != nil {
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		// Keep symlinks, e.g. inside a macOS .app bundle
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		if err := copyFile(path, target); err != nil {
 			return err
 		}
-		defer rc.Close()
+		return os.Chmod(target, info.Mode().Perm())
+	})
+}
 
-		_, err = io.Copy(out, rc)
-		if err != nil {
-			return err
+func moveFilesFromSubdir(src, dest string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("failed to read source directory: %v", err)
+	}
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		destPath := filepath.Join(dest, entry.Name())
+
+		if err := os.Rename(srcPath, destPath); err != nil {
+			return fmt.Errorf("failed to move %s: %v", entry.Name(), err)
 		}
 	}
+
 	return nil
 }
 
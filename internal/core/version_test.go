@@ -0,0 +1,59 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+)
+
+// useManifest replaces VersionManifest for the duration of the test.
+func useManifest(t *testing.T, versions []GodotVersion) {
+	t.Helper()
+	old := VersionManifest
+	t.Cleanup(func() { VersionManifest = old })
+	VersionManifest = versions
+}
+
+func TestGetVersionByIdentifierPrefersVariant(t *testing.T) {
+	setHome(t)
+	standard := GodotVersion{DisplayName: "4.3.0 (Standard)", Version: "4.3.0", OS: runtime.GOOS, Arch: TargetArch}
+	mono := GodotVersion{DisplayName: "4.3.0 (Mono)", Version: "4.3.0", DotNet: true, OS: runtime.GOOS, Arch: TargetArch}
+
+	for _, order := range [][]GodotVersion{{standard, mono}, {mono, standard}} {
+		useManifest(t, order)
+		for _, tt := range []struct {
+			identifier string
+			dotnet     bool
+			want       GodotVersion
+		}{
+			{"4.3.0", false, standard},
+			{"4.3.0", true, mono},
+			// A display name names the variant whatever is asked for
+			{"4.3.0 (Mono)", false, mono},
+			{"4.3.0 (standard)", true, standard},
+		} {
+			got, err := GetVersionByIdentifier(tt.identifier, tt.dotnet)
+			if err != nil {
+				t.Fatalf("GetVersionByIdentifier(%q, %v): %v", tt.identifier, tt.dotnet, err)
+			}
+			if got.DisplayName != tt.want.DisplayName {
+				t.Errorf("GetVersionByIdentifier(%q, %v) with %s first = %s, want %s",
+					tt.identifier, tt.dotnet, order[0].DisplayName, got.DisplayName, tt.want.DisplayName)
+			}
+		}
+	}
+}
+
+func TestGetVersionByIdentifierSingleVariant(t *testing.T) {
+	setHome(t)
+	mono := GodotVersion{DisplayName: "4.3.0 (Mono)", Version: "4.3.0", DotNet: true, OS: runtime.GOOS, Arch: TargetArch}
+	useManifest(t, []GodotVersion{mono})
+
+	// The only variant published is used rather than failing
+	got, err := GetVersionByIdentifier("4.3.0", false)
+	if err != nil || got.DisplayName != mono.DisplayName {
+		t.Errorf("GetVersionByIdentifier(4.3.0) = %s, %v", got.DisplayName, err)
+	}
+	if _, err := GetVersionByIdentifier("4.4.0", false); err == nil {
+		t.Error("GetVersionByIdentifier(4.4.0) found a version")
+	}
+}
@@ -0,0 +1,287 @@
+// Package dotnet generates, checks and builds the C# project of Mono (.NET)
+// Godot projects.
+package dotnet
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/godot"
+	"github.com/IgorBayerl/gdcli/internal/semver"
+)
+
+// SdkPackage is the MSBuild SDK every Godot 4 C# project is built with.
+const SdkPackage = "Godot.NET.Sdk"
+
+const (
+	dotnetSection   = "[dotnet]"
+	assemblyNameKey = "project/assembly_name"
+)
+
+var (
+	sdkNupkg     = regexp.MustCompile(`^Godot\.NET\.Sdk\.(.+)\.nupkg$`)
+	projectSdk   = regexp.MustCompile(`Sdk="Godot\.NET\.Sdk/([^"]+)"`)
+	unsafeName   = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+	unsafeIdent  = regexp.MustCompile(`[^A-Za-z0-9_.]`)
+	sdkListEntry = regexp.MustCompile(`^\d+\.\d+\.\d+\S*\s`)
+)
+
+// Configurations are the build configurations of Godot C# projects, used by
+// the editor and by debug and release exports.
+var Configurations = []string{"Debug", "ExportDebug", "ExportRelease"}
+
+// SdkVersion returns the Godot.NET.Sdk version matching the linked engine,
+// taken from the package shipped in its GodotSharp directory, e.g. "4.3.0",
+// or derived from the engine version, e.g. "4.4.0-beta.1" for 4.4-beta1.
+func SdkVersion(engine *core.ProjectEngine) (string, error) {
+	if sharp := engine.SharpPath(); sharp != "" {
+		entries, _ := os.ReadDir(filepath.Join(sharp, "Tools", "nupkgs"))
+		for _, entry := range entries {
+			if m := sdkNupkg.FindStringSubmatch(entry.Name()); m != nil {
+				return m[1], nil
+			}
+		}
+	}
+
+	v, err := semver.Parse(engine.Version.Version)
+	if err != nil {
+		return "", fmt.Errorf("cannot tell the %s version of %s, register it with --version", SdkPackage, engine.Version.DisplayName)
+	}
+	if v.Major < 4 {
+		return "", fmt.Errorf("C# projects are only generated for Godot 4 and later")
+	}
+
+	version := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Status != "" && v.Status != "stable" {
+		version += fmt.Sprintf("-%s.%d", v.Status, v.StatusNum)
+	}
+	return version, nil
+}
+
+// targetFramework returns the .NET version targeted by projects of the
+// Godot.NET.Sdk version. Godot 4.4 moved from .NET 6 to .NET 8.
+func targetFramework(sdkVersion string) string {
+	if v, err := semver.Parse(sdkVersion); err == nil && (v.Major > 4 || v.Major == 4 && v.Minor >= 4) {
+		return "net8.0"
+	}
+	return "net6.0"
+}
+
+// RequiredSDKMajor returns the oldest .NET SDK major version that builds
+// projects of the Godot.NET.Sdk version.
+func RequiredSDKMajor(sdkVersion string) int {
+	major, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(targetFramework(sdkVersion), "net"), ".0"))
+	return major
+}
+
+// AssemblyName returns the name of the project's assembly, from
+// project.godot or derived from the project name like the editor does.
+func AssemblyName(projectName string) string {
+	if name, ok, err := godot.Setting(dotnetSection, assemblyNameKey); err == nil && ok && name != "" {
+		return name
+	}
+
+	name := unsafeName.ReplaceAllString(strings.TrimSpace(projectName), "_")
+	if name == "" {
+		return "GodotProject"
+	}
+	return name
+}
+
+// rootNamespace turns the assembly name into a valid C# namespace.
+func rootNamespace(assemblyName string) string {
+	namespace := unsafeIdent.ReplaceAllString(assemblyName, "_")
+	if namespace[0] >= '0' && namespace[0] <= '9' {
+		namespace = "_" + namespace
+	}
+	return namespace
+}
+
+// FindProject returns the .csproj in dir, or "" if there is none.
+func FindProject(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.csproj"))
+	if err != nil {
+		return "", err
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("multiple C# projects found: %s", strings.Join(matches, ", "))
+	}
+}
+
+// ProjectSdkVersion returns the Godot.NET.Sdk version the .csproj uses.
+func ProjectSdkVersion(csproj string) (string, error) {
+	data, err := os.ReadFile(csproj)
+	if err != nil {
+		return "", err
+	}
+
+	m := projectSdk.FindStringSubmatch(string(data))
+	if m == nil {
+		return "", fmt.Errorf("%s does not use %s", filepath.Base(csproj), SdkPackage)
+	}
+	return m[1], nil
+}
+
+// GenerateProject writes the .csproj and .sln of the project in the current
+// directory for the Godot.NET.Sdk version, and sets the assembly name in
+// project.godot so the editor uses them. Existing files are kept. It returns
+// the files it created.
+func GenerateProject(projectName, sdkVersion string) ([]string, error) {
+	name := AssemblyName(projectName)
+	var created []string
+
+	csproj := name + ".csproj"
+	if _, err := os.Stat(csproj); os.IsNotExist(err) {
+		if err := os.WriteFile(csproj, []byte(csprojContent(name, sdkVersion)), 0644); err != nil {
+			return created, err
+		}
+		created = append(created, csproj)
+	}
+
+	sln := name + ".sln"
+	if _, err := os.Stat(sln); os.IsNotExist(err) {
+		content, err := slnContent(name, csproj)
+		if err != nil {
+			return created, err
+		}
+		if err := os.WriteFile(sln, []byte(content), 0644); err != nil {
+			return created, err
+		}
+		created = append(created, sln)
+	}
+
+	if _, err := os.Stat(godot.ProjectFile); err == nil {
+		if err := godot.SetString(dotnetSection, assemblyNameKey, name); err != nil {
+			return created, fmt.Errorf("failed to set the assembly name in %s: %v", godot.ProjectFile, err)
+		}
+	}
+	return created, nil
+}
+
+func csprojContent(name, sdkVersion string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<Project Sdk=\"%s/%s\">\n", SdkPackage, sdkVersion)
+	b.WriteString("  <PropertyGroup>\n")
+	fmt.Fprintf(&b, "    <TargetFramework>%s</TargetFramework>\n", targetFramework(sdkVersion))
+	if targetFramework(sdkVersion) == "net6.0" {
+		b.WriteString("    <TargetFramework Condition=\" '$(GodotTargetPlatform)' == 'android' \">net7.0</TargetFramework>\n")
+		b.WriteString("    <TargetFramework Condition=\" '$(GodotTargetPlatform)' == 'ios' \">net8.0</TargetFramework>\n")
+	}
+	b.WriteString("    <EnableDynamicLoading>true</EnableDynamicLoading>\n")
+	if namespace := rootNamespace(name); namespace != name {
+		fmt.Fprintf(&b, "    <RootNamespace>%s</RootNamespace>\n", namespace)
+	}
+	b.WriteString("  </PropertyGroup>\n")
+	b.WriteString("</Project>\n")
+	return b.String()
+}
+
+// slnContent returns a solution with the project in the configurations the
+// editor builds.
+func slnContent(name, csproj string) (string, error) {
+	guid, err := newGUID()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("Microsoft Visual Studio Solution File, Format Version 12.00\n")
+	b.WriteString("# Visual Studio 2012\n")
+	fmt.Fprintf(&b, "Project(\"{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}\") = \"%s\", \"%s\", \"{%s}\"\n", name, csproj, guid)
+	b.WriteString("EndProject\n")
+	b.WriteString("Global\n")
+	b.WriteString("\tGlobalSection(SolutionConfigurationPlatforms) = preSolution\n")
+	for _, c := range Configurations {
+		fmt.Fprintf(&b, "\t%s|Any CPU = %s|Any CPU\n", c, c)
+	}
+	b.WriteString("\tEndGlobalSection\n")
+	b.WriteString("\tGlobalSection(ProjectConfigurationPlatforms) = postSolution\n")
+	for _, c := range Configurations {
+		fmt.Fprintf(&b, "\t\t{%s}.%s|Any CPU.ActiveCfg = %s|Any CPU\n", guid, c, c)
+		fmt.Fprintf(&b, "\t\t{%s}.%s|Any CPU.Build.0 = %s|Any CPU\n", guid, c, c)
+	}
+	b.WriteString("\tEndGlobalSection\n")
+	b.WriteString("EndGlobal\n")
+	return b.String(), nil
+}
+
+func newGUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	// Version 4, RFC 4122 variant
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])), nil
+}
+
+// InstalledSDKs returns the versions of the installed .NET SDKs, newest
+// first.
+func InstalledSDKs() ([]string, error) {
+	if _, err := exec.LookPath("dotnet"); err != nil {
+		return nil, fmt.Errorf("dotnet not found in PATH")
+	}
+
+	out, err := exec.Command("dotnet", "--list-sdks").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list .NET SDKs: %v", err)
+	}
+
+	var sdks []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && sdkListEntry.MatchString(line) {
+			sdks = append(sdks, fields[0])
+		}
+	}
+	sort.Slice(sdks, func(i, j int) bool { return sdkMajor(sdks[i]) > sdkMajor(sdks[j]) })
+	return sdks, nil
+}
+
+func sdkMajor(version string) int {
+	major, _ := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	return major
+}
+
+// CheckSDK returns the newest installed .NET SDK that builds projects of the
+// Godot.NET.Sdk version, or an error telling which one to install.
+func CheckSDK(sdkVersion string) (string, error) {
+	required := RequiredSDKMajor(sdkVersion)
+
+	sdks, err := InstalledSDKs()
+	if err != nil {
+		return "", fmt.Errorf("%v, install the .NET SDK %d or newer from https://dotnet.microsoft.com/download", err, required)
+	}
+	if len(sdks) == 0 || sdkMajor(sdks[0]) < required {
+		installed := "none"
+		if len(sdks) > 0 {
+			installed = strings.Join(sdks, ", ")
+		}
+		return "", fmt.Errorf("%s %s requires the .NET SDK %d or newer (installed: %s), get it from https://dotnet.microsoft.com/download",
+			SdkPackage, sdkVersion, required, installed)
+	}
+	return sdks[0], nil
+}
+
+// Build runs 'dotnet build' for the C# project in the given configuration,
+// e.g. "Debug", printing its output.
+func Build(csproj, configuration string) error {
+	cmd := exec.Command("dotnet", "build", csproj, "--configuration", configuration)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,99 @@
+// Package godot reads and writes Godot's project files.
+package godot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ExportPresetsFile is where the editor saves the export presets.
+const ExportPresetsFile = "export_presets.cfg"
+
+type ExportPreset struct {
+	Name       string
+	Platform   string
+	ExportPath string
+	Runnable   bool
+}
+
+var presetSection = regexp.MustCompile(`^\[preset\.(\d+)\]$`)
+
+// LoadExportPresets reads the presets from export_presets.cfg in order.
+func LoadExportPresets() ([]ExportPreset, error) {
+	file, err := os.Open(ExportPresetsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var presets []ExportPreset
+	var current *ExportPreset
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			if presetSection.MatchString(line) {
+				presets = append(presets, ExportPreset{})
+				current = &presets[len(presets)-1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			current.Name = unquote(value)
+		case "platform":
+			current.Platform = unquote(value)
+		case "export_path":
+			current.ExportPath = unquote(value)
+		case "runnable":
+			current.Runnable = strings.TrimSpace(value) == "true"
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return presets, nil
+}
+
+// FindExportPreset returns the preset with the given name.
+func FindExportPreset(presets []ExportPreset, name string) (ExportPreset, error) {
+	var names []string
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+		names = append(names, fmt.Sprintf("'%s'", p.Name))
+	}
+
+	if len(names) == 0 {
+		return ExportPreset{}, fmt.Errorf("no export presets defined, create them in the editor under Project > Export")
+	}
+	return ExportPreset{}, fmt.Errorf("unknown export preset '%s', available presets: %s", name, strings.Join(names, ", "))
+}
+
+// unquote decodes a Godot string value, leaving other values as they are.
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if s, err := strconv.Unquote(value); err == nil {
+		return s
+	}
+	return strings.Trim(value, `"`)
+}
@@ -0,0 +1,160 @@
+package godot
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProjectFile is the settings file at the root of every Godot project.
+const ProjectFile = "project.godot"
+
+const pluginsSection = "[editor_plugins]"
+
+var quotedString = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// EnablePlugin adds the plugin.cfg at path, e.g.
+// "res://addons/gut/plugin.cfg", to the plugins the editor enables.
+func EnablePlugin(path string) error {
+	return updatePlugins(func(plugins []string) []string {
+		for _, p := range plugins {
+			if p == path {
+				return plugins
+			}
+		}
+		return append(plugins, path)
+	})
+}
+
+// DisablePlugin removes the plugin.cfg at path from the enabled plugins.
+func DisablePlugin(path string) error {
+	return updatePlugins(func(plugins []string) []string {
+		var kept []string
+		for _, p := range plugins {
+			if p != path {
+				kept = append(kept, p)
+			}
+		}
+		return kept
+	})
+}
+
+// updatePlugins rewrites the "enabled" list of the [editor_plugins] section
+// in project.godot, adding the section if the project has none.
+func updatePlugins(update func([]string) []string) error {
+	data, err := os.ReadFile(ProjectFile)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	// Godot 3 projects use PoolStringArray, Godot 4 renamed it
+	arrayType := "PackedStringArray"
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "config_version=4" {
+			arrayType = "PoolStringArray"
+		}
+	}
+
+	section, enabled := findSetting(lines, pluginsSection, "enabled")
+
+	var plugins []string
+	if enabled >= 0 {
+		value := strings.TrimPrefix(strings.TrimSpace(lines[enabled]), "enabled=")
+		if prefix, _, ok := strings.Cut(value, "("); ok {
+			arrayType = prefix
+		}
+		for _, quoted := range quotedString.FindAllString(value, -1) {
+			p, err := strconv.Unquote(quoted)
+			if err != nil {
+				return fmt.Errorf("failed to parse enabled plugins in %s: %v", ProjectFile, err)
+			}
+			plugins = append(plugins, p)
+		}
+	}
+
+	var quoted []string
+	for _, p := range update(plugins) {
+		quoted = append(quoted, strconv.Quote(p))
+	}
+	line := fmt.Sprintf("enabled=%s(%s)", arrayType, strings.Join(quoted, ", "))
+
+	lines = setLine(lines, section, enabled, pluginsSection, line)
+	return os.WriteFile(ProjectFile, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// Setting returns the value of key in the section of project.godot, e.g.
+// "[dotnet]" and "project/assembly_name", with strings unquoted.
+func Setting(section, key string) (string, bool, error) {
+	data, err := os.ReadFile(ProjectFile)
+	if err != nil {
+		return "", false, err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	_, index := findSetting(lines, section, key)
+	if index < 0 {
+		return "", false, nil
+	}
+
+	value := strings.TrimPrefix(strings.TrimSpace(lines[index]), key+"=")
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	return value, true, nil
+}
+
+// SetString sets key in the section of project.godot to a string value,
+// adding the section if the project has none.
+func SetString(section, key, value string) error {
+	data, err := os.ReadFile(ProjectFile)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	sectionIndex, index := findSetting(lines, section, key)
+	lines = setLine(lines, sectionIndex, index, section, key+"="+strconv.Quote(value))
+	return os.WriteFile(ProjectFile, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// findSetting returns the line of the section header and of the key in the
+// section, -1 for each that is missing.
+func findSetting(lines []string, section, key string) (int, int) {
+	sectionIndex := -1
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			if sectionIndex >= 0 {
+				break
+			}
+			if line == section {
+				sectionIndex = i
+			}
+			continue
+		}
+		if sectionIndex >= 0 && strings.HasPrefix(line, key+"=") {
+			return sectionIndex, i
+		}
+	}
+	return sectionIndex, -1
+}
+
+// setLine replaces the line of a setting found with findSetting, or adds it
+// to its section, appending the section if needed.
+func setLine(lines []string, sectionIndex, index int, section, line string) []string {
+	switch {
+	case index >= 0:
+		lines[index] = line
+	case sectionIndex >= 0:
+		lines = append(lines[:sectionIndex+1], append([]string{"", line}, lines[sectionIndex+1:]...)...)
+	default:
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, "", section, "", line, "")
+	}
+	return lines
+}
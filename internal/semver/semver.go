@@ -0,0 +1,304 @@
+// Package semver parses Godot version numbers and version constraints.
+//
+// Godot versions have two or three numeric components followed by an optional
+// release status, e.g. "4.3", "4.3.1", "4.4-rc2", "4.5-dev3" or "4.3-stable".
+// Constraints follow npm conventions: "4.3.0", "4.x", "~4.3", "^4.2",
+// ">=4.2 <4.5" and alternatives joined with "||".
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Release statuses in the order Godot publishes them.
+var statusOrder = map[string]int{
+	"dev":    0,
+	"alpha":  1,
+	"beta":   2,
+	"rc":     3,
+	"stable": 4,
+}
+
+type Version struct {
+	Major     int
+	Minor     int
+	Patch     int
+	Status    string // "dev", "alpha", "beta", "rc" or "stable"
+	StatusNum int    // e.g. 2 for "rc2"
+}
+
+// Parse parses a version like "4.3", "4.3.1", "4.4-rc2" or "v4.5.0-dev3".
+func Parse(s string) (Version, error) {
+	v, parts, err := parseLoose(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if parts < 2 {
+		return Version{}, fmt.Errorf("invalid version '%s': expected at least major and minor version", s)
+	}
+	return v, nil
+}
+
+// parseLoose parses a possibly partial version like "4" and returns how many
+// numeric components were given.
+func parseLoose(s string) (Version, int, error) {
+	input := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	number, status, hasStatus := strings.Cut(s, "-")
+	v := Version{Status: "stable"}
+
+	fields := strings.Split(number, ".")
+	if len(fields) > 3 || number == "" {
+		return Version{}, 0, fmt.Errorf("invalid version '%s'", input)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return Version{}, 0, fmt.Errorf("invalid version '%s'", input)
+		}
+		*nums[i] = n
+	}
+
+	if hasStatus {
+		name := strings.TrimRight(status, "0123456789")
+		if _, ok := statusOrder[name]; !ok {
+			return Version{}, 0, fmt.Errorf("invalid version '%s': unknown status '%s'", input, status)
+		}
+		v.Status = name
+		if digits := status[len(name):]; digits != "" {
+			v.StatusNum, _ = strconv.Atoi(digits)
+		}
+	}
+
+	return v, len(fields), nil
+}
+
+// Prerelease reports whether the version is a dev, alpha, beta or rc build.
+func (v Version) Prerelease() bool {
+	return v.Status != "stable"
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease() {
+		s += fmt.Sprintf("-%s%d", v.Status, v.StatusNum)
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 when a is older than, equal to or newer than b.
+func Compare(a, b Version) int {
+	pairs := [][2]int{
+		{a.Major, b.Major},
+		{a.Minor, b.Minor},
+		{a.Patch, b.Patch},
+		{statusOrder[a.Status], statusOrder[b.Status]},
+		{a.StatusNum, b.StatusNum},
+	}
+	for _, p := range pairs {
+		if p[0] < p[1] {
+			return -1
+		}
+		if p[0] > p[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := Compare(v, c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Constraint is a set of alternatives, each a list of comparators that must
+// all match.
+type Constraint struct {
+	raw          string
+	alternatives [][]comparator
+}
+
+// ParseConstraint parses a constraint like "4.3", "~4.3", ">=4.2 <4.5" or "4.x".
+// A partial version such as "4.3" matches any patch release of it.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+
+	for _, alternative := range strings.Split(s, "||") {
+		terms := constraintTerms(alternative)
+		if len(terms) == 0 {
+			return Constraint{}, fmt.Errorf("invalid constraint '%s'", s)
+		}
+
+		var comparators []comparator
+		for _, term := range terms {
+			parsed, err := parseComparators(term)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("invalid constraint '%s': %v", s, err)
+			}
+			comparators = append(comparators, parsed...)
+		}
+		c.alternatives = append(c.alternatives, comparators)
+	}
+
+	return c, nil
+}
+
+// constraintTerms splits an alternative into its terms. An operator written
+// apart from its version, as in ">= 4.2", belongs to the following term.
+func constraintTerms(alternative string) []string {
+	var terms []string
+	operator := ""
+	for _, field := range strings.Fields(alternative) {
+		if strings.Trim(field, "<>=~^") == "" {
+			operator += field
+			continue
+		}
+		terms = append(terms, operator+field)
+		operator = ""
+	}
+	if operator != "" {
+		terms = append(terms, operator)
+	}
+	return terms
+}
+
+// parseComparators expands a single term of a constraint into comparators.
+func parseComparators(term string) ([]comparator, error) {
+	// An exact version may be partial, "=4.3" is the same as "4.3"
+	if strings.HasPrefix(term, "=") {
+		term = term[1:]
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(term, op) {
+			v, _, err := parseLoose(strings.TrimPrefix(term, op))
+			if err != nil {
+				return nil, err
+			}
+			return []comparator{{op: op, version: v}}, nil
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(term, "~"):
+		v, parts, err := parseLoose(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		upper := Version{Major: v.Major + 1, Status: "dev"}
+		if parts >= 2 {
+			upper = Version{Major: v.Major, Minor: v.Minor + 1, Status: "dev"}
+		}
+		return []comparator{{">=", v}, {"<", upper}}, nil
+
+	case strings.HasPrefix(term, "^"):
+		v, _, err := parseLoose(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		return []comparator{{">=", v}, {"<", Version{Major: v.Major + 1, Status: "dev"}}}, nil
+	}
+
+	// Wildcards and partial versions, e.g. "4.x", "4.3.*" or "4.3". A version
+	// with a status such as "4.3-stable" or "4.4-rc2" is matched exactly, a
+	// status without a number such as "4.5-dev" matches every build of it.
+	var fields []string
+	for _, field := range strings.Split(term, ".") {
+		if field == "x" || field == "X" || field == "*" {
+			break
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	v, parts, err := parseLoose(strings.Join(fields, "."))
+	if err != nil {
+		return nil, err
+	}
+	_, status, hasStatus := strings.Cut(term, "-")
+	switch {
+	case hasStatus && v.Prerelease() && strings.TrimRight(status, "0123456789") == status:
+		return []comparator{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Status: nextStatus(v.Status)}}}, nil
+	case parts == 3 || hasStatus:
+		return []comparator{{"=", v}}, nil
+	case parts == 2:
+		return []comparator{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1, Status: "dev"}}}, nil
+	default:
+		return []comparator{{">=", v}, {"<", Version{Major: v.Major + 1, Status: "dev"}}}, nil
+	}
+}
+
+// Check reports whether the version satisfies the constraint. Pre-releases
+// only match an alternative with a comparator naming a pre-release of the
+// same major, minor and patch version, so ">=4.4-rc1" admits 4.4-rc2 but not
+// 4.5-dev1.
+func (c Constraint) Check(v Version) bool {
+	for _, comparators := range c.alternatives {
+		if v.Prerelease() && !allowsPrerelease(comparators, v) {
+			continue
+		}
+
+		matched := true
+		for _, comp := range comparators {
+			if !comp.check(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPrerelease reports whether one of the comparators names a pre-release
+// of the version's major, minor and patch version. Upper bounds added for
+// ranges such as "~4.3" are the first dev build of a version, which no
+// pre-release is below.
+func allowsPrerelease(comparators []comparator, v Version) bool {
+	for _, comp := range comparators {
+		cv := comp.version
+		if cv.Prerelease() && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// nextStatus returns the release status following status, e.g. "alpha" for
+// "dev".
+func nextStatus(status string) string {
+	for name, order := range statusOrder {
+		if order == statusOrder[status]+1 {
+			return name
+		}
+	}
+	return "stable"
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
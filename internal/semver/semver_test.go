@@ -0,0 +1,187 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"4.3", Version{4, 3, 0, "stable", 0}},
+		{"4.3.1", Version{4, 3, 1, "stable", 0}},
+		{"v4.3.1", Version{4, 3, 1, "stable", 0}},
+		{"4.3-stable", Version{4, 3, 0, "stable", 0}},
+		{"4.4-rc2", Version{4, 4, 0, "rc", 2}},
+		{"4.5.0-dev3", Version{4, 5, 0, "dev", 3}},
+		{"4.5-dev", Version{4, 5, 0, "dev", 0}},
+		{"3.5.3-beta1", Version{3, 5, 3, "beta", 1}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "4", "4.x", "4.3.1.2", "4.3-final", "four.three", "4.-1"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded", in)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[string]string{
+		"4.3":      "4.3.0",
+		"4.4-rc2":  "4.4.0-rc2",
+		"4.5-dev":  "4.5.0-dev0",
+		"v4.2.2":   "4.2.2",
+		"3.5-beta": "3.5.0-beta0",
+	}
+	for in, want := range tests {
+		if got := mustParse(t, in).String(); got != want {
+			t.Errorf("Parse(%q).String() = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Each version is older than the next
+	ordered := []string{"3.5.3", "4.0-dev1", "4.0-alpha2", "4.0-beta1", "4.0-rc1", "4.0-rc2", "4.0", "4.0.1", "4.1-dev1", "4.1", "4.10"}
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := Compare(mustParse(t, ordered[i]), mustParse(t, ordered[j])); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	if Compare(mustParse(t, "4.3"), mustParse(t, "4.3.0-stable")) != 0 {
+		t.Error("4.3 and 4.3.0-stable differ")
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	valid := []string{"4.3", "4.3.0", "=4.3", "= 4.3", "4.x", "4.3.*", "*", "~4.3", "^4.2", ">=4.2 <4.5",
+		">= 4.2 < 4.5", "4.2 || 4.4", "4.4-rc2", "4.5-dev", ">=4.4-rc1"}
+	for _, s := range valid {
+		c, err := ParseConstraint(s)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", s, err)
+		}
+		if c.String() != s {
+			t.Errorf("ParseConstraint(%q).String() = %q", s, c.String())
+		}
+	}
+
+	invalid := []string{"", "   ", "4.3 ||", ">=", "4.3 >=", "latest", "4.3-final", "~", "mybuild"}
+	for _, s := range invalid {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded", s)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		// Exact and partial versions
+		{"4.3.0", "4.3.0", true},
+		{"4.3.0", "4.3.1", false},
+		{"4.3", "4.3.1", true},
+		{"4.3", "4.4", false},
+		{"=4.3", "4.3.1", true},
+		{"= 4.3", "4.3.1", true},
+		{"=4.3", "4.4", false},
+		{"=4.3.0", "4.3.1", false},
+		{"4.3-stable", "4.3.0", true},
+		{"4.3-stable", "4.3.1", false},
+
+		// Wildcards
+		{"4.x", "4.3.1", true},
+		{"4.x", "5.0", false},
+		{"4.3.*", "4.3.2", true},
+		{"4.3.*", "4.4", false},
+		{"*", "3.5.3", true},
+
+		// Tilde and caret ranges
+		{"~4.3", "4.3.5", true},
+		{"~4.3", "4.4", false},
+		{"~4", "4.9", true},
+		{"^4.2", "4.9", true},
+		{"^4.2", "4.1", false},
+		{"^4.2", "5.0", false},
+
+		// Comparators, written with and without spaces
+		{">=4.2 <4.5", "4.4.1", true},
+		{">=4.2 <4.5", "4.5", false},
+		{">= 4.2 < 4.5", "4.4.1", true},
+		{">= 4.2 < 4.5", "4.1", false},
+		{">4.2", "4.2", false},
+		{"<=4.2", "4.2", true},
+
+		// Alternatives
+		{"4.2 || 4.4", "4.4.1", true},
+		{"4.2 || 4.4", "4.3", false},
+
+		// Pre-releases only match when named for the same version
+		{"4.4", "4.4-rc2", false},
+		{"~4.4", "4.4-rc2", false},
+		{"4.x", "4.5-dev1", false},
+		{"4.4-rc2", "4.4-rc2", true},
+		{"4.4-rc2", "4.4-rc3", false},
+		{">=4.4-rc1", "4.4-rc2", true},
+		{">=4.4-rc1", "4.4", true},
+		{">=4.4-rc1", "4.5-dev1", false},
+		{">=4.4-rc1 <4.6", "4.5-dev1", false},
+		{"~4.4-rc1", "4.4.0-rc2", true},
+		{"~4.4-rc1", "4.5-dev1", false},
+		{"4.4-rc1 || 4.x", "4.5-dev1", false},
+		{"4.4-rc1 || 4.x", "4.4-rc1", true},
+		{"4.4-rc1 || 4.x", "4.6", true},
+		{"4.2 || >=4.4-rc1", "4.4-rc2", true},
+
+		// A status without a number matches every build of it
+		{"4.5-dev", "4.5-dev3", true},
+		{"4.5-dev", "4.5-dev", true},
+		{"4.5-dev", "4.5-alpha1", false},
+		{"4.5-dev", "4.5", false},
+		{"4.5-dev", "4.6-dev1", false},
+		{"=4.5-dev", "4.5-dev2", true},
+		{"4.4-rc", "4.4-rc3", true},
+		{"4.4-rc", "4.4", false},
+	}
+
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", tt.constraint, err)
+			continue
+		}
+		if got := c.Check(mustParse(t, tt.version)); got != tt.want {
+			t.Errorf("%q.Check(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func mustParse(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
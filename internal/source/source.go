@@ -0,0 +1,207 @@
+// Package source fetches files from git repositories and archive URLs, as
+// used by templates and addons.
+package source
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/archive"
+	"github.com/IgorBayerl/gdcli/internal/core"
+)
+
+// IsArchiveURL reports whether s is an http(s) URL of a .zip, .tar, .tar.gz
+// or .tgz archive.
+func IsArchiveURL(s string) bool {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return false
+	}
+	name := strings.ToLower(archiveName(s))
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGitURL reports whether s is a git repository URL, optionally followed by
+// "#<ref>".
+func IsGitURL(s string) bool {
+	repo, _, _ := SplitRef(s)
+	return strings.HasPrefix(repo, "git@") ||
+		strings.HasPrefix(repo, "git://") ||
+		strings.HasPrefix(repo, "ssh://") ||
+		strings.HasPrefix(s, "git+") ||
+		strings.HasSuffix(repo, ".git")
+}
+
+// SplitRef splits a git source into the repository URL and the branch, tag or
+// commit after "#", dropping a "git+" prefix.
+func SplitRef(s string) (repo, ref string, hasRef bool) {
+	return strings.Cut(strings.TrimPrefix(s, "git+"), "#")
+}
+
+func archiveName(url string) string {
+	return path.Base(strings.SplitN(url, "?", 2)[0])
+}
+
+var fullCommit = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Git clones the git source into dest, checking out its ref if it has one,
+// and returns the commit that was checked out.
+func Git(s, dest string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required for %s", s)
+	}
+
+	repo, ref, _ := SplitRef(s)
+
+	fmt.Printf("Cloning %s...\n", repo)
+	err := fmt.Errorf("ref %s is a commit", ref)
+	if !fullCommit.MatchString(ref) {
+		args := []string{"clone", "--depth", "1"}
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+		err = runGit(append(args, repo, dest)...)
+	}
+	if err != nil {
+		if ref == "" {
+			return "", err
+		}
+
+		// --branch only accepts branches and tags, a commit needs a full clone
+		os.RemoveAll(dest)
+		if err := runGit("clone", repo, dest); err != nil {
+			return "", err
+		}
+		if err := runGit("-C", dest, "checkout", ref); err != nil {
+			return "", err
+		}
+	}
+
+	return Commit(dest)
+}
+
+// Checkout checks out ref, or the remote's default branch if empty, in a
+// full clone of repo at dir. The clone is created on first use and fetched
+// into afterwards, so it can be reused for every ref. The commit that was
+// checked out is returned.
+func Checkout(repo, ref, dir string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is required for %s", repo)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		fmt.Printf("Cloning %s...\n", repo)
+		tempDir := dir + ".tmp"
+		defer os.RemoveAll(tempDir)
+		os.RemoveAll(tempDir)
+		if err := runGit("clone", repo, tempDir); err != nil {
+			return "", err
+		}
+		if err := os.Rename(tempDir, dir); err != nil {
+			return "", err
+		}
+	} else {
+		fmt.Printf("Fetching %s...\n", repo)
+		if err := runGit("-C", dir, "fetch", "--tags", "--force", "origin"); err != nil {
+			return "", err
+		}
+	}
+
+	// Branches are checked out as fetched from the remote
+	target := "origin/HEAD"
+	if ref != "" {
+		target = ref
+		if err := runGit("-C", dir, "rev-parse", "--verify", "--quiet", "origin/"+ref); err == nil {
+			target = "origin/" + ref
+		}
+	}
+	if err := runGit("-C", dir, "checkout", "--force", "--detach", target); err != nil {
+		return "", err
+	}
+
+	return Commit(dir)
+}
+
+// Commit returns the commit checked out in the git repository at dir.
+func Commit(dir string) (string, error) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func runGit(args ...string) error {
+	// Checking out a tag or commit is expected, skip git's advice about it
+	cmd := exec.Command("git", append([]string{"-c", "advice.detachedHead=false"}, args...)...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %v", args[0], err)
+	}
+	return nil
+}
+
+// Archive downloads the archive at url and extracts it into dest. The
+// download must match expectedSHA512 when it is set. The checksum of the
+// download is returned so it can be pinned.
+func Archive(url, dest, expectedSHA512 string) (string, error) {
+	if err := os.MkdirAll(core.DownloadsDir(), 0755); err != nil {
+		return "", err
+	}
+
+	// Prefix the name with a hash of the URL, as many archives share names
+	// like "main.zip" and a partial download must only be resumed from the
+	// same URL
+	name := archiveName(url)
+	sum := sha256.Sum256([]byte(url))
+	archivePath := filepath.Join(core.DownloadsDir(), hex.EncodeToString(sum[:4])+"-"+name)
+	defer os.Remove(archivePath)
+
+	fmt.Printf("Downloading %s...\n", url)
+	if err := core.DownloadFile(archivePath, url, expectedSHA512); err != nil {
+		return "", fmt.Errorf("failed to download %s: %v", name, err)
+	}
+
+	checksum, err := fileSHA512(archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	// Download URLs without an archive extension, like those of the Asset
+	// Library, serve zip files
+	extract := archive.ExtractZip
+	if IsArchiveURL(url) {
+		extract = archive.Extract
+	}
+	if err := extract(archivePath, dest); err != nil {
+		return "", fmt.Errorf("failed to extract %s: %v", name, err)
+	}
+	return checksum, nil
+}
+
+func fileSHA512(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha512.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,9 @@
+extends Node3D
+
+
+func _ready() -> void:
+	print("{{project_name}} is running")
+
+
+func _process(delta: float) -> void:
+	$Cube.rotate_y(delta)
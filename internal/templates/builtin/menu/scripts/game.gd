@@ -0,0 +1,10 @@
+extends Node2D
+
+# The root keeps processing while paused, game nodes go under World.
+@onready var pause_layer: CanvasLayer = $PauseLayer
+
+
+func _unhandled_input(event: InputEvent) -> void:
+	if event.is_action_pressed("pause"):
+		get_tree().paused = not get_tree().paused
+		pause_layer.visible = get_tree().paused
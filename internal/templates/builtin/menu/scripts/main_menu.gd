@@ -0,0 +1,13 @@
+extends Control
+
+
+func _ready() -> void:
+	$VBoxContainer/PlayButton.grab_focus()
+
+
+func _on_play_pressed() -> void:
+	get_tree().change_scene_to_file("res://scenes/game.tscn")
+
+
+func _on_quit_pressed() -> void:
+	get_tree().quit()
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IgorBayerl/gdcli/internal/core"
+	"github.com/IgorBayerl/gdcli/internal/source"
+)
+
+// CacheDir holds templates fetched from archive and git URLs.
+func CacheDir() string {
+	return filepath.Join(core.GetHomePath(), "templates")
+}
+
+// Resolve returns the template for a source, which is one of:
+//   - the name of a built-in template, e.g. "platformer"
+//   - a local directory
+//   - a .zip, .tar, .tar.gz or .tgz URL
+//   - a git repository URL, optionally followed by "#<ref>"
+//
+// Remote templates are cached and only fetched again once removed from the
+// cache.
+func Resolve(src string) (*Template, error) {
+	// Built-in names win over a directory of the same name, use "./name" for it
+	if !strings.ContainsAny(src, `/\`) {
+		if t, err := Get(src); err == nil {
+			return t, nil
+		}
+	}
+
+	if info, err := os.Stat(src); err == nil && info.IsDir() {
+		return loadDir(src)
+	}
+
+	switch {
+	case source.IsGitURL(src):
+		return resolveGit(src)
+	case source.IsArchiveURL(src):
+		return resolveArchive(src)
+	}
+
+	return Get(src)
+}
+
+// cachePath returns the cache directory for a remote source.
+func cachePath(src string) string {
+	sum := sha256.Sum256([]byte(src))
+	return filepath.Join(CacheDir(), hex.EncodeToString(sum[:8]))
+}
+
+func resolveArchive(url string) (*Template, error) {
+	dir := cachePath(url)
+	if _, err := os.Stat(dir); err == nil {
+		return loadDir(dir)
+	}
+
+	if err := os.MkdirAll(CacheDir(), 0755); err != nil {
+		return nil, err
+	}
+
+	// Extract next to the final directory so a failed extraction is not cached
+	tempDir := dir + ".tmp"
+	defer os.RemoveAll(tempDir)
+	if _, err := source.Archive(url, tempDir, ""); err != nil {
+		return nil, fmt.Errorf("failed to fetch template: %v", err)
+	}
+	if err := os.Rename(tempDir, dir); err != nil {
+		return nil, err
+	}
+
+	return loadDir(dir)
+}
+
+func resolveGit(src string) (*Template, error) {
+	dir := cachePath(src)
+	if _, err := os.Stat(dir); err == nil {
+		return loadDir(dir)
+	}
+
+	if err := os.MkdirAll(CacheDir(), 0755); err != nil {
+		return nil, err
+	}
+	tempDir := dir + ".tmp"
+	defer os.RemoveAll(tempDir)
+
+	if _, err := source.Git(src, tempDir); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tempDir, dir); err != nil {
+		return nil, err
+	}
+	return loadDir(dir)
+}
+
+// loadDir loads the template rooted at the shallowest directory containing a
+// template.json or project.godot, so archives wrapping the template in a
+// top-level folder work too.
+func loadDir(dir string) (*Template, error) {
+	root, err := findRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := os.DirFS(root)
+	t := &Template{files: files}
+	if _, err := fs.Stat(files, DescriptorFile); err == nil {
+		if t, err = load(files); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.Name == "" {
+		t.Name = filepath.Base(root)
+	}
+	return t, nil
+}
+
+func findRoot(dir string) (string, error) {
+	queue := []string{dir}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, marker := range []string{DescriptorFile, "project.godot"} {
+			if _, err := os.Stat(filepath.Join(current, marker)); err == nil {
+				return current, nil
+			}
+		}
+
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return "", err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && entry.Name() != ".git" {
+				queue = append(queue, filepath.Join(current, entry.Name()))
+			}
+		}
+	}
+	return "", fmt.Errorf("no %s or project.godot found in template %s", DescriptorFile, dir)
+}
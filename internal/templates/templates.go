@@ -0,0 +1,187 @@
+// Package templates provides the project templates used by 'gdcli init',
+// either built in or loaded from a directory, archive or git repository.
+package templates
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/IgorBayerl/gdcli/internal/semver"
+)
+
+// DescriptorFile describes a template and is not copied into the project.
+const DescriptorFile = "template.json"
+
+//go:embed all:builtin
+var builtinFS embed.FS
+
+type Template struct {
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	EngineVersion    string     `json:"engine_version"`     // Constraint the engine must satisfy, e.g. "4.x"
+	MinEngineVersion string     `json:"min_engine_version"` // Oldest engine version supported, e.g. "4.2"
+	Variables        []Variable `json:"variables"`
+
+	files fs.FS // Root of the template files
+}
+
+// Variable is a value substituted for "{{name}}" in the template files.
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"` // Shown when prompting for the value
+	Default     string `json:"default"`
+}
+
+// Builtin returns the templates shipped with gdcli, sorted by name.
+func Builtin() ([]*Template, error) {
+	entries, err := fs.ReadDir(builtinFS, "builtin")
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*Template
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		files, err := fs.Sub(builtinFS, path.Join("builtin", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := load(files)
+		if err != nil {
+			return nil, fmt.Errorf("invalid built-in template %s: %v", entry.Name(), err)
+		}
+		templates = append(templates, t)
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// Get returns the built-in template with the given name.
+func Get(name string) (*Template, error) {
+	templates, err := Builtin()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("unknown template '%s', available templates: %s", name, strings.Join(names, ", "))
+}
+
+func load(files fs.FS) (*Template, error) {
+	data, err := fs.ReadFile(files, DescriptorFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", DescriptorFile, err)
+	}
+	t.files = files
+	return &t, nil
+}
+
+// CheckEngine returns an error if the template cannot be used with the engine
+// version. Pre-releases count as the release they lead up to, so a "4.x"
+// template can be used with 4.4-rc2.
+func (t *Template) CheckEngine(version string) error {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return err
+	}
+	v.Status, v.StatusNum = "stable", 0
+
+	if t.MinEngineVersion != "" {
+		min, err := semver.Parse(t.MinEngineVersion)
+		if err != nil {
+			return fmt.Errorf("template %s: %v", t.Name, err)
+		}
+		if semver.Compare(v, min) < 0 {
+			return fmt.Errorf("template %s requires Godot %s or newer, but the project uses %s", t.Name, t.MinEngineVersion, version)
+		}
+	}
+
+	if t.EngineVersion != "" {
+		c, err := semver.ParseConstraint(t.EngineVersion)
+		if err != nil {
+			return fmt.Errorf("template %s: %v", t.Name, err)
+		}
+		if !c.Check(v) {
+			return fmt.Errorf("template %s requires Godot %s, but the project uses %s", t.Name, t.EngineVersion, version)
+		}
+	}
+
+	return nil
+}
+
+// Apply copies the template into dest, replacing "{{name}}" placeholders in
+// text files with the variables. Existing files are kept unless overwrite is
+// set.
+func (t *Template) Apply(dest string, vars map[string]string, overwrite bool) error {
+	var pairs []string
+	for name, value := range vars {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return fs.WalkDir(t.files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." || p == DescriptorFile {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		if _, err := os.Stat(target); err == nil && !overwrite {
+			fmt.Printf("Keeping existing %s\n", p)
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := fs.ReadFile(t.files, p)
+		if err != nil {
+			return err
+		}
+		if utf8.Valid(data) {
+			data = []byte(replacer.Replace(string(data)))
+		}
+
+		// Keep the mode of the source, e.g. the executable bit of scripts,
+		// but writable by the owner as embedded files are read-only
+		mode := info.Mode().Perm() | 0200
+		if err := os.WriteFile(target, data, mode); err != nil {
+			return err
+		}
+		return os.Chmod(target, mode)
+	})
+}
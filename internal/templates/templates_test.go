@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestApplyKeepsFileModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no file permission bits")
+	}
+	src := t.TempDir()
+	files := map[string]os.FileMode{
+		"project.godot":     0644,
+		"tools/build.sh":    0755,
+		"secrets/local.cfg": 0600,
+	}
+	for name, mode := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("name = {{name}}"), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpl, err := loadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	// Overwriting an existing file gives it the mode of the source too
+	if err := os.WriteFile(filepath.Join(dest, "project.godot"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpl.Apply(dest, map[string]string{"name": "game"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range files {
+		path := filepath.Join(dest, filepath.FromSlash(name))
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s has mode %v, want %v", name, got, want)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "name = game" {
+			t.Errorf("%s contains %q", name, data)
+		}
+	}
+}
+
+func TestApplyBuiltinIsWritable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no file permission bits")
+	}
+	tmpl, err := Get("2d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	if err := tmpl.Apply(dest, nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(dest, "project.godot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0644 {
+		t.Errorf("project.godot has mode %v, want 0644", got)
+	}
+}